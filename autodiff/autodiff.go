@@ -0,0 +1,331 @@
+// Package autodiff implements tape-based reverse-mode automatic differentiation
+// over ml.Matrix operations.
+//
+// A cost is written with the Node operations recorded on a Tape, Backward
+// then processes the gradient of the cost for each variable:
+//
+//	tape := autodiff.NewTape()
+//	θ := tape.Var(theta)
+//	cost := tape.Const(x).Mul(θ).Sub(tape.Const(y)).Square().Mean()
+//	tape.Backward(cost)
+//	// θ.Grad holds ∂cost/∂θ.
+package autodiff
+
+import (
+	"errors"
+	"math"
+
+	"github.com/creack/ml"
+)
+
+// Common errors.
+var (
+	ErrNotScalar    = errors.New("backward needs a (1,1) output node")
+	ErrMixedTapes   = errors.New("nodes are recorded on different tapes")
+	ErrUnknownInput = errors.New("node is not recorded on the tape")
+)
+
+// Tape records the operations in evaluation order.
+type Tape struct {
+	nodes []*Node
+}
+
+// NewTape instantiates a new empty tape.
+func NewTape() *Tape {
+	return &Tape{}
+}
+
+// Node is a matrix value recorded on a tape.
+// Grad is set by Tape.Backward for the nodes depending on a variable.
+type Node struct {
+	Value ml.Matrix
+	Grad  ml.Matrix
+
+	tape      *Tape
+	needsGrad bool
+	backward  func() // Propagates Grad to the inputs.
+}
+
+// Var records a variable: Backward processes its gradient.
+// NOTE: Not a copy, the node value is the given matrix.
+func (t *Tape) Var(ma ml.Matrix) *Node {
+	return t.record(ma, true, nil)
+}
+
+// Const records a constant, i.e. the dataset: no gradient is processed for it.
+// NOTE: Not a copy, the node value is the given matrix.
+func (t *Tape) Const(ma ml.Matrix) *Node {
+	return t.record(ma, false, nil)
+}
+
+// Scalar records a (1,1) constant.
+func (t *Tape) Scalar(x float64) *Node {
+	return t.Const(ml.Matrix{{x}})
+}
+
+// Len returns the number of recorded nodes.
+func (t *Tape) Len() int {
+	return len(t.nodes)
+}
+
+func (t *Tape) record(value ml.Matrix, needsGrad bool, backward func()) *Node {
+	n := &Node{Value: value, tape: t, needsGrad: needsGrad, backward: backward}
+	t.nodes = append(t.nodes, n)
+	return n
+}
+
+// op records the result of an operation on the given inputs.
+// The backward function is only kept when one of the inputs needs a gradient.
+func (t *Tape) op(value ml.Matrix, backward func(out *Node), inputs ...*Node) *Node {
+	needsGrad := false
+	for _, in := range inputs {
+		if in.tape != t {
+			panic(ErrMixedTapes)
+		}
+		needsGrad = needsGrad || in.needsGrad
+	}
+	if !needsGrad {
+		return t.record(value, false, nil)
+	}
+	var out *Node
+	out = t.record(value, true, func() { backward(out) })
+	return out
+}
+
+// accumulate adds the given gradient to the node gradient.
+func (n *Node) accumulate(grad ml.Matrix) {
+	if !n.needsGrad {
+		return
+	}
+	if n.Grad == nil {
+		n.Grad = grad
+		return
+	}
+	n.Grad = n.Grad.Add(grad)
+}
+
+// Backward processes the gradient of the given (1,1) output for all the
+// nodes recorded before it. Previous gradients are reset.
+// panic with ErrNotScalar if out is not (1,1) and ErrUnknownInput if out is
+// not recorded on the tape.
+func (t *Tape) Backward(out *Node) {
+	if out.tape != t {
+		panic(ErrUnknownInput)
+	}
+	if m, n := out.Value.Dim(); m != 1 || n != 1 {
+		panic(ErrNotScalar)
+	}
+	for _, node := range t.nodes {
+		node.Grad = nil
+	}
+	out.accumulate(ml.Matrix{{1}})
+	for i := len(t.nodes) - 1; i >= 0; i-- {
+		if node := t.nodes[i]; node.backward != nil && node.Grad != nil {
+			node.backward()
+		}
+	}
+}
+
+// Add returns n + n2.
+func (n *Node) Add(n2 *Node) *Node {
+	return n.tape.op(n.Value.Add(n2.Value), func(out *Node) {
+		n.accumulate(out.Grad)
+		n2.accumulate(out.Grad)
+	}, n, n2)
+}
+
+// Sub returns n - n2.
+func (n *Node) Sub(n2 *Node) *Node {
+	return n.tape.op(n.Value.Sub(n2.Value), func(out *Node) {
+		n.accumulate(out.Grad)
+		n2.accumulate(out.Grad.Scale(-1))
+	}, n, n2)
+}
+
+// Mul returns the matrix product n * n2.
+func (n *Node) Mul(n2 *Node) *Node {
+	return n.tape.op(n.Value.Mul(n2.Value), func(out *Node) {
+		if n.needsGrad {
+			n.accumulate(out.Grad.Mul(n2.Value.Transpose()))
+		}
+		if n2.needsGrad {
+			n2.accumulate(n.Value.Transpose().Mul(out.Grad))
+		}
+	}, n, n2)
+}
+
+// Scale returns the scalar multiplication k * n.
+func (n *Node) Scale(k float64) *Node {
+	return n.tape.op(n.Value.Scale(k), func(out *Node) {
+		n.accumulate(out.Grad.Scale(k))
+	}, n)
+}
+
+// Transpose returns the transposed n.
+func (n *Node) Transpose() *Node {
+	return n.tape.op(n.Value.Transpose(), func(out *Node) {
+		n.accumulate(out.Grad.Transpose())
+	}, n)
+}
+
+// MulElem returns the element-wise (Hadamard) product of n and n2.
+// panic with ml.ErrBadDim if the dimensions differ.
+func (n *Node) MulElem(n2 *Node) *Node {
+	return n.tape.op(mulElem(n.Value, n2.Value), func(out *Node) {
+		n.accumulate(mulElem(out.Grad, n2.Value))
+		n2.accumulate(mulElem(out.Grad, n.Value))
+	}, n, n2)
+}
+
+// AddRow adds the given (1,n) row to each row of n, i.e. a bias.
+// panic with ml.ErrBadDim if the dimensions do not match.
+func (n *Node) AddRow(row *Node) *Node {
+	rows, cols := n.Value.Dim()
+	if m, k := row.Value.Dim(); m != 1 || k != cols {
+		panic(ml.ErrBadDim)
+	}
+	ret := ml.NewMatrix(rows, cols)
+	for i, line := range n.Value {
+		for j, elem := range line {
+			ret[i][j] = elem + row.Value[0][j]
+		}
+	}
+	return n.tape.op(ret, func(out *Node) {
+		n.accumulate(out.Grad)
+		sum := ml.NewMatrix(1, cols)
+		for _, line := range out.Grad {
+			for j, elem := range line {
+				sum[0][j] += elem
+			}
+		}
+		row.accumulate(sum)
+	}, n, row)
+}
+
+// Apply applies f element-wise. df is the derivative of f, called with
+// the input element x and the output element y = f(x).
+func (n *Node) Apply(f func(x float64) float64, df func(x, y float64) float64) *Node {
+	ret := ml.NewMatrix(n.Value.Dim())
+	for i, line := range n.Value {
+		for j, elem := range line {
+			ret[i][j] = f(elem)
+		}
+	}
+	return n.tape.op(ret, func(out *Node) {
+		grad := ml.NewMatrix(n.Value.Dim())
+		for i, line := range n.Value {
+			for j, elem := range line {
+				grad[i][j] = out.Grad[i][j] * df(elem, out.Value[i][j])
+			}
+		}
+		n.accumulate(grad)
+	}, n)
+}
+
+// Square returns the element-wise square of n.
+func (n *Node) Square() *Node {
+	return n.Apply(func(x float64) float64 { return x * x }, func(x, _ float64) float64 { return 2 * x })
+}
+
+// Exp returns the element-wise e^n.
+func (n *Node) Exp() *Node {
+	return n.Apply(math.Exp, func(_, y float64) float64 { return y })
+}
+
+// Log returns the element-wise natural logarithm of n.
+func (n *Node) Log() *Node {
+	return n.Apply(math.Log, func(x, _ float64) float64 { return 1 / x })
+}
+
+// Abs returns the element-wise absolute value of n.
+// The derivative at 0 is 0.
+func (n *Node) Abs() *Node {
+	return n.Apply(math.Abs, func(x, _ float64) float64 {
+		switch {
+		case x > 0:
+			return 1
+		case x < 0:
+			return -1
+		}
+		return 0
+	})
+}
+
+// Sigmoid returns the element-wise logistic function of n.
+func (n *Node) Sigmoid() *Node {
+	return n.Apply(ml.Sigmoid, func(_, y float64) float64 { return y * (1 - y) })
+}
+
+// Tanh returns the element-wise hyperbolic tangent of n.
+func (n *Node) Tanh() *Node {
+	return n.Apply(math.Tanh, func(_, y float64) float64 { return 1 - y*y })
+}
+
+// ReLU returns the element-wise max(n, 0).
+func (n *Node) ReLU() *Node {
+	return n.Apply(func(x float64) float64 { return math.Max(x, 0) }, func(x, _ float64) float64 {
+		if x > 0 {
+			return 1
+		}
+		return 0
+	})
+}
+
+// Sum returns the (1,1) sum of all the elements of n.
+func (n *Node) Sum() *Node {
+	var sum ml.Accumulator
+	for _, line := range n.Value {
+		for _, elem := range line {
+			sum.Add(elem)
+		}
+	}
+	return n.tape.op(ml.Matrix{{sum.Sum()}}, func(out *Node) {
+		grad := ml.NewMatrix(n.Value.Dim())
+		for _, line := range grad {
+			for j := range line {
+				line[j] = out.Grad[0][0]
+			}
+		}
+		n.accumulate(grad)
+	}, n)
+}
+
+// Mean returns the (1,1) mean of all the elements of n.
+func (n *Node) Mean() *Node {
+	rows, cols := n.Value.Dim()
+	return n.Sum().Scale(1 / float64(rows*cols))
+}
+
+// mulElem returns the element-wise product of the given matrices.
+func mulElem(ma1, ma2 ml.Matrix) ml.Matrix {
+	if !ma1.DimMatch(ma2) {
+		panic(ml.ErrBadDim)
+	}
+	ret := ml.NewMatrix(ma1.Dim())
+	for i, line := range ma1 {
+		for j, elem := range line {
+			ret[i][j] = elem * ma2[i][j]
+		}
+	}
+	return ret
+}
+
+// Grad evaluates the given cost function on a new tape and returns its
+// value with the gradient for each of the given parameters.
+// The cost function receives one variable node per parameter.
+func Grad(cost func(t *Tape, params []*Node) *Node, params ...ml.Matrix) (float64, []ml.Matrix) {
+	t := NewTape()
+	vars := make([]*Node, len(params))
+	for i, param := range params {
+		vars[i] = t.Var(param)
+	}
+	out := cost(t, vars)
+	t.Backward(out)
+	grads := make([]ml.Matrix, len(vars))
+	for i, v := range vars {
+		if grads[i] = v.Grad; grads[i] == nil {
+			grads[i] = ml.NewMatrix(v.Value.Dim())
+		}
+	}
+	return out.Value[0][0], grads
+}
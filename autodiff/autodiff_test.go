@@ -0,0 +1,148 @@
+package autodiff_test
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/creack/ml"
+	"github.com/creack/ml/autodiff"
+)
+
+var testDataset = ml.Dataset{
+	X: ml.Matrix{{1, 1, 2}, {1, 2, 0.5}, {1, 3, -1}, {1, 4, 1.5}},
+	Y: ml.Vector{{1}, {0}, {1}, {1}},
+}
+
+// numericGradient returns the central finite difference gradient of f for the given parameter.
+func numericGradient(f func() float64, param ml.Matrix) ml.Matrix {
+	ret := ml.NewMatrix(param.Dim())
+	for i, line := range param {
+		for j := range line {
+			old := line[j]
+			line[j] = old + 1e-6
+			c1 := f()
+			line[j] = old - 1e-6
+			c2 := f()
+			line[j] = old
+			ret[i][j] = (c1 - c2) / 2e-6
+		}
+	}
+	return ret
+}
+
+func assertClose(t *testing.T, name string, expect, got ml.Matrix) {
+	t.Helper()
+	if !expect.DimMatch(got) {
+		t.Fatalf("%s: unexpected gradient dimension.\nExpect:\t%v\nGot:\t%v", name, expect, got)
+	}
+	for i, line := range expect {
+		for j, elem := range line {
+			if math.Abs(elem-got[i][j]) > 1e-6 {
+				t.Fatalf("%s: gradient mismatch at (%d,%d).\nExpect:\t%v\nGot:\t%v", name, i, j, expect, got)
+			}
+		}
+	}
+}
+
+func TestLinearRegressionGradient(t *testing.T) {
+	lr := ml.LinearRegression{Θ: ml.Vector{{0.5}, {-1}, {2}}}
+	cost, grads := autodiff.Grad(func(tape *autodiff.Tape, params []*autodiff.Node) *autodiff.Node {
+		diff := tape.Const(testDataset.X).Mul(params[0]).Sub(tape.Const(ml.Matrix(testDataset.Y)))
+		return diff.Square().Mean().Scale(0.5)
+	}, ml.Matrix(lr.Θ))
+
+	if expect, got := stringify(lr.SquaredError(testDataset)), stringify(cost); expect != got {
+		t.Fatalf("Unexpected cost.\nExpect:\t%s\nGot:\t%s", expect, got)
+	}
+	for j := range lr.Θ {
+		if expect, got := stringify(lr.PartialDerivative(testDataset, j)), stringify(grads[0][j][0]); expect != got {
+			t.Fatalf("Unexpected partial derivative for Θ[%d].\nExpect:\t%s\nGot:\t%s", j, expect, got)
+		}
+	}
+}
+
+func TestOperationsGradient(t *testing.T) {
+	w := ml.Matrix{{0.1, -0.2}, {0.3, 0.4}, {-0.5, 0.6}}
+	b := ml.Matrix{{0.05, -0.1}}
+	for _, elem := range []struct {
+		name string
+		cost func(tape *autodiff.Tape, w, b *autodiff.Node) *autodiff.Node
+	}{
+		{"tanh", func(tape *autodiff.Tape, w, b *autodiff.Node) *autodiff.Node {
+			return tape.Const(testDataset.X).Mul(w).AddRow(b).Tanh().Sum()
+		}},
+		{"sigmoid-log", func(tape *autodiff.Tape, w, b *autodiff.Node) *autodiff.Node {
+			return tape.Const(testDataset.X).Mul(w).AddRow(b).Sigmoid().Log().Mean().Scale(-1)
+		}},
+		{"exp-mulelem", func(tape *autodiff.Tape, w, b *autodiff.Node) *autodiff.Node {
+			return w.MulElem(w.Exp()).Sub(w.Scale(3)).Transpose().Mul(w).Sum()
+		}},
+		{"abs-relu", func(tape *autodiff.Tape, w, b *autodiff.Node) *autodiff.Node {
+			return w.Abs().Sum().Add(tape.Const(testDataset.X).Mul(w).AddRow(b).ReLU().Mean())
+		}},
+		{"reuse", func(tape *autodiff.Tape, w, b *autodiff.Node) *autodiff.Node {
+			h := tape.Const(testDataset.X).Mul(w)
+			return h.MulElem(h).Sum().Add(h.Sum()).Add(b.Mul(b.Transpose()))
+		}},
+	} {
+		cost := func() float64 {
+			ret, _ := autodiff.Grad(func(tape *autodiff.Tape, params []*autodiff.Node) *autodiff.Node {
+				return elem.cost(tape, params[0], params[1])
+			}, w, b)
+			return ret
+		}
+		_, grads := autodiff.Grad(func(tape *autodiff.Tape, params []*autodiff.Node) *autodiff.Node {
+			return elem.cost(tape, params[0], params[1])
+		}, w, b)
+		assertClose(t, elem.name+" w", numericGradient(cost, w), grads[0])
+		assertClose(t, elem.name+" b", numericGradient(cost, b), grads[1])
+	}
+}
+
+func TestUnusedVariable(t *testing.T) {
+	_, grads := autodiff.Grad(func(tape *autodiff.Tape, params []*autodiff.Node) *autodiff.Node {
+		return params[0].Sum()
+	}, ml.Matrix{{1, 2}}, ml.Matrix{{3}, {4}})
+	assertClose(t, "used", ml.Matrix{{1, 1}}, grads[0])
+	assertClose(t, "unused", ml.Matrix{{0}, {0}}, grads[1])
+}
+
+func TestConstNoGrad(t *testing.T) {
+	tape := autodiff.NewTape()
+	x := tape.Const(ml.Matrix{{1, 2}})
+	w := tape.Var(ml.Matrix{{3}, {4}})
+	out := x.Mul(w)
+	tape.Backward(out)
+	if x.Grad != nil {
+		t.Fatalf("Unexpected gradient for a constant: %v", x.Grad)
+	}
+	assertClose(t, "w", ml.Matrix{{1}, {2}}, w.Grad)
+
+	// Backward resets the previous gradients.
+	tape.Backward(out)
+	assertClose(t, "w twice", ml.Matrix{{1}, {2}}, w.Grad)
+}
+
+func TestBackwardNotScalar(t *testing.T) {
+	defer func() {
+		if err := recover(); err != autodiff.ErrNotScalar {
+			t.Fatalf("Unexpected panic: %v", err)
+		}
+	}()
+	tape := autodiff.NewTape()
+	tape.Backward(tape.Var(ml.Matrix{{1, 2}}))
+}
+
+func TestMixedTapes(t *testing.T) {
+	defer func() {
+		if err := recover(); err != autodiff.ErrMixedTapes {
+			t.Fatalf("Unexpected panic: %v", err)
+		}
+	}()
+	autodiff.NewTape().Var(ml.Matrix{{1}}).Add(autodiff.NewTape().Var(ml.Matrix{{1}}))
+}
+
+func stringify(f float64) string {
+	return fmt.Sprintf("%.6f", f)
+}
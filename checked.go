@@ -0,0 +1,147 @@
+package ml
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sync/atomic"
+)
+
+// ErrNonFinite is the base error for NaN/Inf values produced by an operation.
+var ErrNonFinite = errors.New("non-finite value")
+
+// NonFiniteError reports the operation which first produced a NaN/Inf value.
+type NonFiniteError struct {
+	Op       string  // Name of the operation, i.e. "Mul".
+	Row, Col int     // Index of the first non-finite element of the result, -1 for scalars.
+	Value    float64 // The non-finite value.
+}
+
+func (e *NonFiniteError) Error() string {
+	if e.Row < 0 {
+		return fmt.Sprintf("%s produced a non-finite value %v", e.Op, e.Value)
+	}
+	return fmt.Sprintf("%s produced a non-finite value %v at (%d,%d)", e.Op, e.Value, e.Row, e.Col)
+}
+
+// Unwrap allows errors.Is(err, ErrNonFinite).
+func (e *NonFiniteError) Unwrap() error {
+	return ErrNonFinite
+}
+
+// checked is the package-level checked numerics mode flag.
+var checked int32
+
+// SetChecked enables or disables the checked numerics mode.
+// When enabled, every matrix operation and cost function verifies that its result
+// is finite and panics with a *NonFiniteError naming the operation otherwise.
+// Train, Resume, LRRangeTest, BFGS, LBFGS and the GradientDescent methods recover
+// the panic and return the error. Wrap direct calls, i.e. Mul, Inverse or
+// SquaredError, with CheckedCall to get the error instead of the panic.
+// NOTE: Checking costs a full scan of each result, it is disabled by default.
+func SetChecked(enabled bool) {
+	var v int32
+	if enabled {
+		v = 1
+	}
+	atomic.StoreInt32(&checked, v)
+}
+
+// Checked returns whether the checked numerics mode is enabled.
+func Checked() bool {
+	return atomic.LoadInt32(&checked) == 1
+}
+
+// check verifies the result of the given operation when in checked mode.
+// Returns the matrix for chaining.
+func (ma Matrix) check(op string) Matrix {
+	if !Checked() {
+		return ma
+	}
+	for i, line := range ma {
+		for j, elem := range line {
+			if !isFinite(elem) {
+				panic(&NonFiniteError{Op: op, Row: i, Col: j, Value: elem})
+			}
+		}
+	}
+	return ma
+}
+
+// checkValue verifies the scalar result of the given operation when in checked mode.
+func checkValue(op string, x float64) float64 {
+	if Checked() && !isFinite(x) {
+		panic(&NonFiniteError{Op: op, Row: -1, Col: -1, Value: x})
+	}
+	return x
+}
+
+// CheckedCall calls fn and returns the *NonFiniteError it panics with in checked mode,
+// nil if fn completes. Any other panic is forwarded.
+// i.e. err := CheckedCall(func() { ret = a.Mul(b) }).
+func CheckedCall(fn func()) (err error) {
+	defer recoverNonFinite(&err)
+	fn()
+	return nil
+}
+
+// recoverNonFinite recovers a *NonFiniteError panic and stores it in the given error.
+// Any other panic is forwarded.
+// Meant to be deferred.
+func recoverNonFinite(err *error) {
+	if r := recover(); r != nil {
+		e, ok := r.(*NonFiniteError)
+		if !ok {
+			panic(r)
+		}
+		*err = e
+	}
+}
+
+func isFinite(x float64) bool {
+	return !math.IsNaN(x) && !math.IsInf(x, 0)
+}
+
+// HasNaN checks if any element of the current matrix is NaN.
+func (ma Matrix) HasNaN() bool {
+	for _, line := range ma {
+		for _, elem := range line {
+			if math.IsNaN(elem) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// HasInf checks if any element of the current matrix is infinite.
+func (ma Matrix) HasInf() bool {
+	for _, line := range ma {
+		for _, elem := range line {
+			if math.IsInf(elem, 0) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// IsFinite checks that all the elements of the current matrix are neither NaN nor infinite.
+func (ma Matrix) IsFinite() bool {
+	return !ma.HasNaN() && !ma.HasInf()
+}
+
+// HasNaN checks if any element of the current vector is NaN.
+func (v Vector) HasNaN() bool {
+	return Matrix(v).HasNaN()
+}
+
+// HasInf checks if any element of the current vector is infinite.
+func (v Vector) HasInf() bool {
+	return Matrix(v).HasInf()
+}
+
+// IsFinite checks that all the elements of the current vector are neither NaN nor infinite.
+func (v Vector) IsFinite() bool {
+	return Matrix(v).IsFinite()
+}
@@ -0,0 +1,104 @@
+package ml_test
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/creack/ml"
+)
+
+func TestFinite(t *testing.T) {
+	for i, elem := range []struct {
+		m                      ml.Matrix
+		hasNaN, hasInf, finite bool
+	}{
+		{ml.Matrix{}, false, false, true},
+		{ml.Matrix{{1, 2}, {3, 4}}, false, false, true},
+		{ml.Matrix{{1, math.NaN()}, {3, 4}}, true, false, false},
+		{ml.Matrix{{1, 2}, {math.Inf(-1), 4}}, false, true, false},
+		{ml.Matrix{{math.Inf(1), math.NaN()}}, true, true, false},
+	} {
+		if got := elem.m.HasNaN(); got != elem.hasNaN {
+			t.Errorf("[%d] Unexpected HasNaN: %t", i, got)
+		}
+		if got := elem.m.HasInf(); got != elem.hasInf {
+			t.Errorf("[%d] Unexpected HasInf: %t", i, got)
+		}
+		if got := elem.m.IsFinite(); got != elem.finite {
+			t.Errorf("[%d] Unexpected IsFinite: %t", i, got)
+		}
+	}
+}
+
+func TestCheckedMode(t *testing.T) {
+	m1 := ml.Matrix{{1e308, 1}, {1, 1}}
+
+	// Unchecked: overflow goes silently.
+	if ret := m1.Scale(10); ret.IsFinite() {
+		t.Fatalf("Expected overflow in scale result\n%s\n", ret)
+	}
+
+	ml.SetChecked(true)
+	defer ml.SetChecked(false)
+
+	for i, elem := range []struct {
+		op  string
+		fct func()
+	}{
+		{"Scale", func() { m1.Scale(10) }},
+		{"Add", func() { m1.Add(m1) }},
+		{"Mul", func() { m1.Mul(m1) }},
+		{"Sum", func() { (ml.Vector{{1e308}, {1e308}}).Sum() }},
+	} {
+		func() {
+			defer func() {
+				e, ok := recover().(*ml.NonFiniteError)
+				if !ok {
+					t.Fatalf("[%d] Expected a *NonFiniteError panic", i)
+				}
+				if e.Op != elem.op {
+					t.Fatalf("[%d] Unexpected operation in error.\nExpect:\t%s\nGot:\t%s", i, elem.op, e.Op)
+				}
+				if !errors.Is(e, ml.ErrNonFinite) {
+					t.Fatalf("[%d] Error does not match ErrNonFinite: %s", i, e)
+				}
+			}()
+			elem.fct()
+		}()
+	}
+
+	// Direct calls get the error instead of the panic.
+	var nf *ml.NonFiniteError
+	if err := ml.CheckedCall(func() { m1.Mul(m1) }); !errors.As(err, &nf) || nf.Op != "Mul" {
+		t.Fatalf("Unexpected error for a checked call: %v", err)
+	}
+	if err := ml.CheckedCall(func() { m1.Scale(0.5) }); err != nil {
+		t.Fatalf("Unexpected error for a finite checked call: %s", err)
+	}
+}
+
+func TestGradientDescentDiverge(t *testing.T) {
+	var testSimpleDataset = ml.Dataset{
+		X: ml.Matrix{
+			{1},
+			{2},
+			{3},
+		},
+		Y: ml.Vector{
+			{1},
+			{2},
+			{3},
+		},
+	}
+	// A too large alpha makes Θ diverge, the descent needs to abort.
+	lr := &ml.LinearRegression{Θ: ml.Vector{{-0.1}, {3}}}
+	var nf *ml.NonFiniteError
+	if _, err := lr.GradientDescent(context.Background(), testSimpleDataset, 10, ml.GradientDescentOptions()); !errors.As(err, &nf) {
+		t.Fatalf("Expected a non-finite error, got: %v", err)
+	}
+	if cost := lr.SquaredError(testSimpleDataset); !math.IsInf(cost, 1) {
+		t.Fatalf("Expected diverged parameters, got: %s", lr)
+	}
+}
@@ -0,0 +1,195 @@
+package ml
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+)
+
+// Checkpoint errors.
+var (
+	ErrUnsupportedCheckpoint = errors.New("unsupported checkpoint version")
+	ErrUnknownOptimizer      = errors.New("unknown optimizer kind")
+)
+
+// checkpointVersion is the current version of the checkpoint format.
+//   - 1: initial version.
+const checkpointVersion = 1
+
+// Checkpoint is a snapshot of a training, see TrainOptions.CheckpointPath and Resume.
+// It is stored as JSON.
+type Checkpoint struct {
+	Version int `json:"version"`
+
+	Parameters Vector `json:"parameters"`
+
+	// Optimizer kind, see RegisterOptimizer, and its JSON encoded state.
+	OptimizerKind  string          `json:"optimizer_kind"`
+	OptimizerState json.RawMessage `json:"optimizer_state"`
+
+	RandState uint64 `json:"rand_state"` // State of TrainOptions.Rand.
+
+	Iterations  int       `json:"iterations"`
+	Evaluations int       `json:"evaluations"`
+	History     []float64 `json:"history"`
+
+	// Early stopping state, set when trained with a validation dataset.
+	ValidationHistory []float64 `json:"validation_history,omitempty"`
+	ValidationCost    float64   `json:"validation_cost,omitempty"`
+	BestIteration     int       `json:"best_iteration,omitempty"`
+	BestParameters    Vector    `json:"best_parameters,omitempty"`
+	BestCost          float64   `json:"best_cost,omitempty"`
+	SinceBest         int       `json:"since_best,omitempty"`
+}
+
+// optimizers is the optimizer registry, by kind and by type.
+var (
+	optimizers     = map[string]func() Optimizer{}
+	optimizerKinds = map[reflect.Type]string{}
+)
+
+// RegisterOptimizer registers an optimizer kind so it can be checkpointed.
+// The optimizer state needs to be stored in exported fields, encoded as JSON.
+// newOptimizer returns a new zero optimizer, a pointer.
+func RegisterOptimizer(kind string, newOptimizer func() Optimizer) {
+	optimizers[kind] = newOptimizer
+	optimizerKinds[reflect.TypeOf(newOptimizer())] = kind
+}
+
+func init() {
+	RegisterOptimizer("sgd", func() Optimizer { return &SGD{} })
+	RegisterOptimizer("momentum", func() Optimizer { return &Momentum{} })
+	RegisterOptimizer("nesterov", func() Optimizer { return &Nesterov{} })
+	RegisterOptimizer("adagrad", func() Optimizer { return &AdaGrad{} })
+	RegisterOptimizer("rmsprop", func() Optimizer { return &RMSProp{} })
+	RegisterOptimizer("adam", func() Optimizer { return &Adam{} })
+	RegisterOptimizer("backtracking", func() Optimizer { return &Backtracking{} })
+}
+
+// newCheckpoint snapshots the current training state.
+func newCheckpoint(θ Vector, opt Optimizer, rnd *RNG, res TrainResult, v *validator) (*Checkpoint, error) {
+	kind, ok := optimizerKinds[reflect.TypeOf(opt)]
+	if !ok {
+		return nil, fmt.Errorf("%w: %T", ErrUnknownOptimizer, opt)
+	}
+	state, err := json.Marshal(opt)
+	if err != nil {
+		return nil, err
+	}
+	cp := &Checkpoint{
+		Version:           checkpointVersion,
+		Parameters:        θ,
+		OptimizerKind:     kind,
+		OptimizerState:    state,
+		RandState:         rnd.State,
+		Iterations:        res.Iterations,
+		Evaluations:       res.Evaluations,
+		History:           res.History,
+		ValidationHistory: res.ValidationHistory,
+		ValidationCost:    res.ValidationCost,
+		BestIteration:     res.BestIteration,
+	}
+	if v != nil && v.best != nil {
+		cp.BestParameters, cp.BestCost, cp.SinceBest = Vector(v.best), v.bestCost, v.sinceBest
+	}
+	return cp, nil
+}
+
+// WriteTo writes the checkpoint as JSON. Implements io.WriterTo.
+func (cp *Checkpoint) WriteTo(w io.Writer) (int64, error) {
+	buf, err := json.Marshal(cp)
+	if err != nil {
+		return 0, err
+	}
+	n, err := w.Write(append(buf, '\n'))
+	return int64(n), err
+}
+
+// save writes the checkpoint to the given file, atomically replacing it.
+func (cp *Checkpoint) save(path string) error {
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(f.Name()) }() // Best effort cleanup on failure.
+	if _, err := cp.WriteTo(f); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+// ReadCheckpoint reads a checkpoint written by a training.
+// Only the current format version is supported, other versions fail with
+// ErrUnsupportedCheckpoint.
+func ReadCheckpoint(r io.Reader) (*Checkpoint, error) {
+	cp := &Checkpoint{}
+	if err := json.NewDecoder(r).Decode(cp); err != nil {
+		return nil, err
+	}
+	switch cp.Version {
+	case checkpointVersion:
+	default:
+		return nil, fmt.Errorf("%w: %d", ErrUnsupportedCheckpoint, cp.Version)
+	}
+	return cp, nil
+}
+
+// LoadCheckpoint reads the checkpoint from the given file.
+func LoadCheckpoint(path string) (*Checkpoint, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+	return ReadCheckpoint(f)
+}
+
+// Optimizer returns the checkpointed optimizer, with its state.
+func (cp *Checkpoint) Optimizer() (Optimizer, error) {
+	newOptimizer, ok := optimizers[cp.OptimizerKind]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownOptimizer, cp.OptimizerKind)
+	}
+	opt := newOptimizer()
+	if err := json.Unmarshal(cp.OptimizerState, opt); err != nil {
+		return nil, err
+	}
+	return opt, nil
+}
+
+// Resume continues the training saved in the given checkpoint file, exactly where it
+// stopped: the model parameters, the optimizer state, the shuffling and the iteration
+// count are restored from the checkpoint. The model needs to have the same
+// architecture as the checkpointed one, i.e. the same layer sizes.
+// opts is the same as for Train, opts.Rand and opts.MaxIterations include the
+// iterations before the checkpoint.
+// Returns ErrBadDim if the model parameter count does not match the checkpoint.
+func Resume(ctx context.Context, model Model, dataset Dataset, path string, opts TrainOptions) (TrainResult, error) {
+	cp, err := LoadCheckpoint(path)
+	if err != nil {
+		return TrainResult{}, err
+	}
+	θ := model.Parameters()
+	if len(θ) != len(cp.Parameters) {
+		return TrainResult{}, fmt.Errorf("checkpoint has %d parameters, model %d: %w", len(cp.Parameters), len(θ), ErrBadDim)
+	}
+	if cp.BestParameters != nil && len(cp.BestParameters) != len(θ) {
+		return TrainResult{}, fmt.Errorf("checkpoint has %d best parameters, model %d: %w", len(cp.BestParameters), len(θ), ErrBadDim)
+	}
+	opt, err := cp.Optimizer()
+	if err != nil {
+		return TrainResult{}, err
+	}
+	Matrix(θ).SetSubMatrix(Matrix(cp.Parameters), 0, 0)
+	opts.Rand = &RNG{State: cp.RandState}
+	return train(ctx, model, dataset, opt, opts, cp)
+}
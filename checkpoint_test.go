@@ -0,0 +1,114 @@
+package ml_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/creack/ml"
+)
+
+func TestResume(t *testing.T) {
+	newNetwork := func() *ml.MLP {
+		return ml.NewMLP([]int{1, 6, 1}, []ml.Activation{ml.ActivationTanh, ml.ActivationIdentity}, ml.NewRNG(5))
+	}
+	opts := ml.TrainOptions{
+		MaxIterations: 40,
+		BatchSize:     2,
+		Rand:          ml.NewRNG(9),
+		Schedule:      ml.CosineAnnealing{Initial: 0.05, Min: 0.001, Period: 40},
+		Validation:    testValidationDataset,
+		ValidateEvery: 3,
+	}
+
+	// Uninterrupted training.
+	expect := newNetwork()
+	expectRes, err := ml.Train(context.Background(), expect, testOverfitDataset, &ml.Adam{}, opts)
+	if err != nil {
+		t.Fatalf("Error training the network: %s", err)
+	}
+
+	// Interrupted after 20 iterations, resumed from the checkpoint with a fresh network.
+	path := filepath.Join(t.TempDir(), "checkpoint.json")
+	opts.Rand, opts.MaxIterations, opts.CheckpointPath, opts.CheckpointEvery = ml.NewRNG(9), 20, path, 5
+	if _, err := ml.Train(context.Background(), newNetwork(), testOverfitDataset, &ml.Adam{}, opts); err != nil {
+		t.Fatalf("Error training the network: %s", err)
+	}
+	cp, err := ml.LoadCheckpoint(path)
+	if err != nil {
+		t.Fatalf("Error loading the checkpoint: %s", err)
+	}
+	if cp.Iterations != 20 || cp.OptimizerKind != "adam" {
+		t.Fatalf("Unexpected checkpoint: %d iterations, %q optimizer", cp.Iterations, cp.OptimizerKind)
+	}
+
+	got := ml.NewMLP([]int{1, 6, 1}, []ml.Activation{ml.ActivationTanh, ml.ActivationIdentity}, ml.NewRNG(42))
+	opts.MaxIterations, opts.Rand = 40, nil
+	gotRes, err := ml.Resume(context.Background(), got, testOverfitDataset, path, opts)
+	if err != nil {
+		t.Fatalf("Error resuming the training: %s", err)
+	}
+	if !ml.Matrix(got.Parameters()).Equal(ml.Matrix(expect.Parameters())) {
+		t.Fatalf("Resumed training differs.\nExpect:\t%v\nGot:\t%v", expect.Parameters(), got.Parameters())
+	}
+	if gotRes.Iterations != expectRes.Iterations || gotRes.Evaluations != expectRes.Evaluations ||
+		gotRes.BestIteration != expectRes.BestIteration || len(gotRes.History) != len(expectRes.History) ||
+		len(gotRes.ValidationHistory) != len(expectRes.ValidationHistory) {
+		t.Fatalf("Resumed result differs.\nExpect:\t%+v\nGot:\t%+v", expectRes, gotRes)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("Checkpoint file missing: %s", err)
+	}
+}
+
+func TestCheckpointErrors(t *testing.T) {
+	for i, elem := range []struct {
+		data   string
+		expect error
+	}{
+		{`{"version":99}`, ml.ErrUnsupportedCheckpoint},
+		{`{"version":0,"parameters":{"rows":1,"cols":1,"data":[1]}}`, ml.ErrUnsupportedCheckpoint},
+		{`{"parameters":null}`, ml.ErrUnsupportedCheckpoint},
+	} {
+		if _, err := ml.ReadCheckpoint(strings.NewReader(elem.data)); !errors.Is(err, elem.expect) {
+			t.Errorf("[%d] Unexpected error: %v", i, err)
+		}
+	}
+
+	cp, err := ml.ReadCheckpoint(strings.NewReader(`{"version":1,"parameters":{"rows":1,"cols":1,"data":[1]},"optimizer_kind":"unknown","history":[1]}`))
+	if err != nil {
+		t.Fatalf("Error reading the checkpoint: %s", err)
+	}
+	if _, err := cp.Optimizer(); !errors.Is(err, ml.ErrUnknownOptimizer) {
+		t.Fatalf("Unexpected error for an unknown optimizer: %v", err)
+	}
+
+	// Parameters not matching the model.
+	path := filepath.Join(t.TempDir(), "checkpoint.json")
+	lr := &ml.LinearRegression{Θ: ml.NewVector(2)}
+	for i, data := range []string{
+		`{"version":1,"parameters":{"rows":3,"cols":1,"data":[1,2,3]},"optimizer_kind":"sgd","optimizer_state":{},"history":[1]}`,
+		`{"version":1,"optimizer_kind":"sgd","optimizer_state":{},"history":[1]}`,
+		`{"version":1,"parameters":{"rows":2,"cols":1,"data":[1,2]},"best_parameters":{"rows":1,"cols":1,"data":[1]},"optimizer_kind":"sgd","optimizer_state":{},"history":[1]}`,
+	} {
+		if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+			t.Fatalf("[%d] Error writing the checkpoint: %s", i, err)
+		}
+		if _, err := ml.Resume(context.Background(), lr, testOverfitDataset, path, ml.TrainOptions{}); !errors.Is(err, ml.ErrBadDim) {
+			t.Errorf("[%d] Unexpected error for a parameter count mismatch: %v", i, err)
+		}
+	}
+
+	// Unregistered optimizers can not be checkpointed.
+	opts := ml.TrainOptions{MaxIterations: 1, CheckpointPath: path}
+	if _, err := ml.Train(context.Background(), lr, testOverfitDataset, unregisteredOptimizer{}, opts); !errors.Is(err, ml.ErrUnknownOptimizer) {
+		t.Fatalf("Unexpected error for an unregistered optimizer: %v", err)
+	}
+}
+
+type unregisteredOptimizer struct{}
+
+func (unregisteredOptimizer) Step(θ, grad ml.Vector) {}
@@ -0,0 +1,98 @@
+package ml
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// CSVOptions configures the CSV matrix reader/writer.
+type CSVOptions struct {
+	Comma   rune // Field delimiter, defaults to ','.
+	Comment rune // When set, lines starting with it are ignored on read.
+	Header  bool // When set, the first line is read as column names.
+}
+
+// csvReader returns a csv reader configured with the current options.
+func (opts CSVOptions) csvReader(r io.Reader) *csv.Reader {
+	cr := csv.NewReader(r)
+	if opts.Comma != 0 {
+		cr.Comma = opts.Comma
+	}
+	cr.Comment = opts.Comment
+	cr.TrimLeadingSpace = true
+	return cr
+}
+
+// ReadCSVMatrix reads a matrix from CSV data, one row per line.
+// When opts.Header is set, the first line is returned as column names.
+// All the lines need to have the same number of fields.
+func ReadCSVMatrix(r io.Reader, opts CSVOptions) (Matrix, []string, error) {
+	cr := opts.csvReader(r)
+
+	var header []string
+	if opts.Header {
+		var err error
+		if header, err = cr.Read(); err != nil {
+			if err == io.EOF {
+				return Matrix{}, nil, nil
+			}
+			return nil, nil, err
+		}
+	}
+
+	ret := Matrix{}
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		line, _ := cr.FieldPos(0)
+		row := make(MRow, len(record))
+		for j, field := range record {
+			if row[j], err = strconv.ParseFloat(field, 64); err != nil {
+				return nil, nil, fmt.Errorf("line %d, column %d: %w", line, j+1, err)
+			}
+		}
+		ret = append(ret, row)
+	}
+	return ret, header, nil
+}
+
+// WriteCSVMatrix writes the given matrix as CSV, one row per line.
+// When the given header is not empty, it is written first and needs to have
+// one name per column.
+// opts.Comment and opts.Header are ignored.
+func WriteCSVMatrix(w io.Writer, ma Matrix, header []string, opts CSVOptions) error {
+	if err := ma.Validate(); err != nil {
+		return err
+	}
+	cw := csv.NewWriter(w)
+	if opts.Comma != 0 {
+		cw.Comma = opts.Comma
+	}
+	if len(header) > 0 {
+		if _, n := ma.shape(); len(ma) > 0 && n != len(header) {
+			return ErrBadDim
+		}
+		if err := cw.Write(header); err != nil {
+			return err
+		}
+	}
+	record := make([]string, 0)
+	for _, line := range ma {
+		record = record[:0]
+		for _, elem := range line {
+			record = append(record, strconv.FormatFloat(elem, 'g', -1, 64))
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,59 @@
+package ml_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/creack/ml"
+)
+
+func TestReadCSVMatrix(t *testing.T) {
+	in := `# Housing prices.
+size;rooms;price
+2104; 3; 399900
+1600; 3; 329900
+`
+	ret, header, err := ml.ReadCSVMatrix(strings.NewReader(in), ml.CSVOptions{Comma: ';', Comment: '#', Header: true})
+	if err != nil {
+		t.Fatalf("Error reading csv: %s", err)
+	}
+	if expect, got := "size,rooms,price", strings.Join(header, ","); expect != got {
+		t.Fatalf("Unexpected header.\nExpect:\t%s\nGot:\t%s", expect, got)
+	}
+	if expect := (ml.Matrix{{2104, 3, 399900}, {1600, 3, 329900}}); !ret.Equal(expect) {
+		t.Fatalf("Unexpected matrix\nGot:\n%s\nExpect:\n%s\n", ret, expect)
+	}
+
+	if _, _, err := ml.ReadCSVMatrix(strings.NewReader("1,2\n3,x\n"), ml.CSVOptions{}); err == nil || !strings.Contains(err.Error(), "line 2, column 2") {
+		t.Fatalf("Unexpected error for invalid value: %v", err)
+	}
+	if _, _, err := ml.ReadCSVMatrix(strings.NewReader("1,2\n3\n"), ml.CSVOptions{}); err == nil {
+		t.Fatal("Expected an error for inconsistent field count")
+	}
+}
+
+func TestCSVMatrixRoundTrip(t *testing.T) {
+	m1 := ml.Matrix{
+		{1, 2.5, -3},
+		{1e-10, 5, 6},
+	}
+	header := []string{"a", "b", "c"}
+	buf := bytes.NewBuffer(nil)
+	if err := ml.WriteCSVMatrix(buf, m1, header, ml.CSVOptions{Comma: '\t'}); err != nil {
+		t.Fatalf("Error writing csv: %s", err)
+	}
+	if expect, got := "a\tb\tc\n1\t2.5\t-3\n1e-10\t5\t6\n", buf.String(); expect != got {
+		t.Fatalf("Unexpected csv.\nExpect:\t%q\nGot:\t%q", expect, got)
+	}
+	ret, header2, err := ml.ReadCSVMatrix(buf, ml.CSVOptions{Comma: '\t', Header: true})
+	if err != nil {
+		t.Fatalf("Error reading csv: %s", err)
+	}
+	if strings.Join(header, ",") != strings.Join(header2, ",") || !ret.Equal(m1) {
+		t.Fatalf("Unexpected round trip %v\n%s\n", header2, ret)
+	}
+	if err := ml.WriteCSVMatrix(buf, m1, []string{"a"}, ml.CSVOptions{}); err != ml.ErrBadDim {
+		t.Fatalf("Unexpected error for header mismatch: %v", err)
+	}
+}
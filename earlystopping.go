@@ -0,0 +1,59 @@
+package ml
+
+import "math"
+
+// validator tracks the validation cost of a training for early stopping.
+type validator struct {
+	dataset  Dataset
+	every    int
+	patience int
+
+	best          Matrix  // Parameters with the lowest validation cost.
+	bestCost      float64 // Training cost of the best parameters.
+	sinceBest     int     // Evaluations since the last improvement.
+	bestIteration int
+}
+
+// newValidator returns the validator for the given validation dataset, nil without one.
+// See TrainOptions.Validation for every and patience.
+func newValidator(dataset Dataset, every, patience int) *validator {
+	if len(dataset.X) == 0 {
+		return nil
+	}
+	if every <= 0 {
+		every = 1
+	}
+	return &validator{dataset: dataset, every: every, patience: patience}
+}
+
+// validate evaluates the validation cost when due at the given iteration.
+// Returns NaN when not evaluated, and whether the training needs to stop early.
+func (v *validator) validate(model Model, res *TrainResult) (float64, bool) {
+	if v == nil || res.Iterations%v.every != 0 {
+		return math.NaN(), false
+	}
+	c := model.Cost(v.dataset)
+	res.ValidationHistory = append(res.ValidationHistory, c)
+	if len(res.ValidationHistory) == 1 || c < res.ValidationCost {
+		θ := model.Parameters()
+		if v.best == nil {
+			v.best = Matrix(θ).Copy()
+		} else {
+			v.best.SetSubMatrix(Matrix(θ), 0, 0)
+		}
+		res.ValidationCost, res.BestIteration = c, res.Iterations
+		v.bestCost, v.sinceBest = res.Cost, 0
+		return c, false
+	}
+	v.sinceBest++
+	return c, v.patience > 0 && v.sinceBest >= v.patience
+}
+
+// restore sets the parameters with the lowest validation cost back into the model.
+func (v *validator) restore(model Model, res *TrainResult) {
+	if v == nil || v.best == nil {
+		return
+	}
+	Matrix(model.Parameters()).SetSubMatrix(v.best, 0, 0)
+	res.Cost = v.bestCost
+}
@@ -0,0 +1,101 @@
+package ml_test
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/creack/ml"
+)
+
+// Noisy samples of y = x, the training set being small enough to overfit.
+var (
+	testOverfitDataset = ml.Dataset{
+		X: ml.Matrix{{-1}, {-0.5}, {0}, {0.5}, {1}},
+		Y: ml.Vector{{-0.7}, {-0.8}, {0.3}, {0.2}, {1.2}},
+	}
+	testValidationDataset = ml.Dataset{
+		X: ml.Matrix{{-0.75}, {-0.25}, {0.25}, {0.75}},
+		Y: ml.Vector{{-0.75}, {-0.25}, {0.25}, {0.75}},
+	}
+)
+
+func TestEarlyStopping(t *testing.T) {
+	nn := ml.NewMLP([]int{1, 16, 1}, []ml.Activation{ml.ActivationTanh, ml.ActivationIdentity}, ml.NewRNG(3))
+	opts := ml.TrainOptions{MaxIterations: 20000, Validation: testValidationDataset, ValidateEvery: 10, Patience: 20}
+	res, err := ml.Train(context.Background(), nn, testOverfitDataset, &ml.Adam{Alpha: 0.01}, opts)
+	if err != nil {
+		t.Fatalf("Error training the network: %s", err)
+	}
+	if res.Reason != ml.TerminationEarlyStopping {
+		t.Fatalf("Unexpected termination: %s (%d iterations)", res.Reason, res.Iterations)
+	}
+	if expect, got := res.Iterations/10+1, len(res.ValidationHistory); expect != got {
+		t.Fatalf("Unexpected validation count.\nExpect:\t%d\nGot:\t%d", expect, got)
+	}
+	if expect, got := res.BestIteration+20*10, res.Iterations; expect != got {
+		t.Fatalf("Unexpected stop iteration.\nExpect:\t%d\nGot:\t%d", expect, got)
+	}
+	// The best parameters are restored.
+	if expect, got := stringify(res.ValidationCost), stringify(nn.Cost(testValidationDataset)); expect != got {
+		t.Fatalf("Unexpected validation cost of the final parameters.\nExpect:\t%s\nGot:\t%s", expect, got)
+	}
+	if expect, got := stringify(res.History[res.BestIteration]), stringify(res.Cost); expect != got {
+		t.Fatalf("Unexpected reported cost.\nExpect:\t%s\nGot:\t%s", expect, got)
+	}
+	for _, elem := range res.ValidationHistory {
+		if elem < res.ValidationCost {
+			t.Fatalf("Validation cost %g lower than the best one %g", elem, res.ValidationCost)
+		}
+	}
+}
+
+func TestValidationRestore(t *testing.T) {
+	// Without patience, trains until the end but still restores the best parameters.
+	var events []ml.Event
+	collect := ml.ObserverFunc(func(e ml.Event) { events = append(events, e) })
+	lr := &ml.LinearRegression{Θ: ml.NewVector(2)}
+	opts := ml.TrainOptions{MaxIterations: 50, Validation: testValidationDataset, ValidateEvery: 5, Observers: []ml.Observer{collect}}
+	res, err := ml.Train(context.Background(), lr, testOverfitDataset, &ml.SGD{Alpha: 0.1}, opts)
+	if err != nil {
+		t.Fatalf("Error training the model: %s", err)
+	}
+	if res.Reason != ml.TerminationMaxIterations || len(res.ValidationHistory) != 11 {
+		t.Fatalf("Unexpected result: %+v", res)
+	}
+	if expect, got := stringify(res.ValidationCost), stringify(lr.Cost(testValidationDataset)); expect != got {
+		t.Fatalf("Unexpected validation cost of the final parameters.\nExpect:\t%s\nGot:\t%s", expect, got)
+	}
+	for _, e := range events {
+		if evaluated := !math.IsNaN(e.ValidationCost); evaluated != (e.Iteration%5 == 0) {
+			t.Fatalf("Unexpected validation cost for iteration %d: %g", e.Iteration, e.ValidationCost)
+		}
+	}
+}
+
+func TestQuasiNewtonEarlyStopping(t *testing.T) {
+	for name, minimize := range map[string]func(context.Context, ml.Vector, func(ml.Dataset) float64, func(ml.Dataset) ml.Vector, ml.Dataset, ml.QuasiNewtonOptions) (ml.TrainResult, error){
+		"bfgs":  ml.BFGS,
+		"lbfgs": ml.LBFGS,
+	} {
+		nn := ml.NewMLP([]int{1, 16, 1}, []ml.Activation{ml.ActivationTanh, ml.ActivationIdentity}, ml.NewRNG(3))
+		opts := ml.QuasiNewtonOptions{MaxIterations: 2000, Validation: testValidationDataset, Patience: 10}
+		res, err := minimize(context.Background(), nn.Parameters(), nn.Cost, nn.Gradient, testOverfitDataset, opts)
+		if err != nil {
+			t.Fatalf("%s: error training the network: %s", name, err)
+		}
+		if res.Reason != ml.TerminationEarlyStopping || res.Iterations != res.BestIteration+10 {
+			t.Fatalf("%s: unexpected termination: %s (%d iterations, best %d)", name, res.Reason, res.Iterations, res.BestIteration)
+		}
+		if expect, got := res.Iterations+1, len(res.ValidationHistory); expect != got {
+			t.Fatalf("%s: unexpected validation count.\nExpect:\t%d\nGot:\t%d", name, expect, got)
+		}
+		// The best parameters are restored.
+		if expect, got := stringify(res.ValidationCost), stringify(nn.Cost(testValidationDataset)); expect != got {
+			t.Fatalf("%s: unexpected validation cost of the final parameters.\nExpect:\t%s\nGot:\t%s", name, expect, got)
+		}
+		if expect, got := stringify(res.History[res.BestIteration]), stringify(res.Cost); expect != got {
+			t.Fatalf("%s: unexpected reported cost.\nExpect:\t%s\nGot:\t%s", name, expect, got)
+		}
+	}
+}
@@ -0,0 +1,209 @@
+package ml
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"math"
+)
+
+// Encoding errors.
+var (
+	ErrInvalidEncoding     = errors.New("invalid matrix encoding")
+	ErrUnsupportedEncoding = errors.New("unsupported matrix encoding version")
+)
+
+// binaryVersion is the current version of the binary matrix layout.
+const binaryVersion = 1
+
+// jsonMatrix is the JSON wire format of a matrix.
+// Data is stored row-major: element (i,j) is data[i*cols+j].
+type jsonMatrix struct {
+	Rows int       `json:"rows"`
+	Cols int       `json:"cols"`
+	Data []float64 `json:"data"`
+}
+
+// shape returns the storage shape of the current matrix.
+// Unlike Dim, an empty row has 0 columns.
+func (ma Matrix) shape() (int, int) {
+	if len(ma) == 0 {
+		return 0, 0
+	}
+	return len(ma), len(ma[0])
+}
+
+// fromData instantiates a (m,n) matrix from the given row-major data and validates it.
+// Rows without columns, or columns without rows, are rejected: the shape is not backed
+// by any data and would be trusted as-is. The shape is checked before m*n can overflow.
+func fromData(m, n int, data []float64) (Matrix, error) {
+	if m < 0 || n < 0 || (m == 0) != (n == 0) || (n != 0 && m > math.MaxInt/n) {
+		return nil, ErrInvalidEncoding
+	}
+	if len(data) != m*n {
+		return nil, ErrInvalidEncoding
+	}
+	ret := NewMatrix(m, n)
+	for i, line := range ret {
+		copy(line, data[i*n:])
+	}
+	if err := ret.Validate(); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// MarshalJSON implements json.Marshaler.
+// The matrix is encoded as {"rows":m,"cols":n,"data":[...]} with row-major data.
+func (ma Matrix) MarshalJSON() ([]byte, error) {
+	if ma == nil {
+		return []byte("null"), nil
+	}
+	if err := ma.Validate(); err != nil {
+		return nil, err
+	}
+	m, n := ma.shape()
+	data := make([]float64, 0, m*n)
+	for _, line := range ma {
+		data = append(data, line...)
+	}
+	return json.Marshal(jsonMatrix{Rows: m, Cols: n, Data: data})
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+// Accepts the shape header format as well as plain nested arrays.
+// The decoded matrix is validated.
+func (ma *Matrix) UnmarshalJSON(buf []byte) error {
+	buf = bytes.TrimSpace(buf)
+	if bytes.Equal(buf, []byte("null")) {
+		return nil
+	}
+	// Legacy format: nested arrays.
+	if len(buf) > 0 && buf[0] == '[' {
+		var rows [][]float64
+		if err := json.Unmarshal(buf, &rows); err != nil {
+			return err
+		}
+		ret := make(Matrix, len(rows))
+		for i, line := range rows {
+			ret[i] = line
+		}
+		if err := ret.Validate(); err != nil {
+			return err
+		}
+		*ma = ret
+		return nil
+	}
+	var jm jsonMatrix
+	if err := json.Unmarshal(buf, &jm); err != nil {
+		return err
+	}
+	ret, err := fromData(jm.Rows, jm.Cols, jm.Data)
+	if err != nil {
+		return err
+	}
+	*ma = ret
+	return nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler, which gob also relies on.
+// Layout (little-endian):
+//   - version: uint8
+//   - rows, cols: uint32
+//   - data: rows*cols float64, row-major.
+//
+// A nil matrix is encoded as an empty one.
+func (ma Matrix) MarshalBinary() ([]byte, error) {
+	if ma == nil {
+		ma = Matrix{}
+	}
+	if err := ma.Validate(); err != nil {
+		return nil, err
+	}
+	m, n := ma.shape()
+	if uint64(m) > math.MaxUint32 || uint64(n) > math.MaxUint32 {
+		return nil, ErrBadDim
+	}
+	buf := make([]byte, 9+8*m*n)
+	buf[0] = binaryVersion
+	binary.LittleEndian.PutUint32(buf[1:], uint32(m))
+	binary.LittleEndian.PutUint32(buf[5:], uint32(n))
+	off := 9
+	for _, line := range ma {
+		for _, elem := range line {
+			binary.LittleEndian.PutUint64(buf[off:], math.Float64bits(elem))
+			off += 8
+		}
+	}
+	return buf, nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+// The decoded matrix is validated.
+func (ma *Matrix) UnmarshalBinary(buf []byte) error {
+	if len(buf) < 9 {
+		return ErrInvalidEncoding
+	}
+	if buf[0] != binaryVersion {
+		return ErrUnsupportedEncoding
+	}
+	m, n := binary.LittleEndian.Uint32(buf[1:]), binary.LittleEndian.Uint32(buf[5:])
+	buf = buf[9:]
+	if len(buf)%8 != 0 || uint64(len(buf)/8) != uint64(m)*uint64(n) {
+		return ErrInvalidEncoding
+	}
+	data := make([]float64, len(buf)/8)
+	for i := range data {
+		data[i] = math.Float64frombits(binary.LittleEndian.Uint64(buf[8*i:]))
+	}
+	ret, err := fromData(int(m), int(n), data)
+	if err != nil {
+		return err
+	}
+	*ma = ret
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler.
+// Same format as Matrix with cols set to 1.
+func (v Vector) MarshalJSON() ([]byte, error) {
+	return Matrix(v).MarshalJSON()
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+// The decoded vector is validated.
+func (v *Vector) UnmarshalJSON(buf []byte) error {
+	var ma Matrix
+	if err := ma.UnmarshalJSON(buf); err != nil {
+		return err
+	}
+	if ma == nil {
+		return nil
+	}
+	if err := Vector(ma).Validate(); err != nil {
+		return err
+	}
+	*v = Vector(ma)
+	return nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+// Same layout as Matrix with cols set to 1.
+func (v Vector) MarshalBinary() ([]byte, error) {
+	return Matrix(v).MarshalBinary()
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+// The decoded vector is validated.
+func (v *Vector) UnmarshalBinary(buf []byte) error {
+	var ma Matrix
+	if err := ma.UnmarshalBinary(buf); err != nil {
+		return err
+	}
+	if err := Vector(ma).Validate(); err != nil {
+		return err
+	}
+	*v = Vector(ma)
+	return nil
+}
@@ -0,0 +1,157 @@
+package ml_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/creack/ml"
+)
+
+func TestMatrixJSON(t *testing.T) {
+	m1 := ml.Matrix{
+		{1, 2, 3},
+		{4, 5, 6.5},
+	}
+	buf, err := json.Marshal(m1)
+	if err != nil {
+		t.Fatalf("Error encoding matrix: %s", err)
+	}
+	if expect, got := `{"rows":2,"cols":3,"data":[1,2,3,4,5,6.5]}`, string(buf); expect != got {
+		t.Fatalf("Unexpected JSON encoding.\nExpect:\t%s\nGot:\t%s", expect, got)
+	}
+	var m2 ml.Matrix
+	if err := json.Unmarshal(buf, &m2); err != nil {
+		t.Fatalf("Error decoding matrix: %s", err)
+	}
+	if !m1.Equal(m2) {
+		t.Fatalf("Unexpected decoded matrix\nGot:\n%s\nExpect:\n%s\n", m2, m1)
+	}
+
+	// Nested arrays are still supported.
+	var m3 ml.Matrix
+	if err := json.Unmarshal([]byte(`[[1,2,3],[4,5,6.5]]`), &m3); err != nil {
+		t.Fatalf("Error decoding nested arrays: %s", err)
+	}
+	if !m1.Equal(m3) {
+		t.Fatalf("Unexpected decoded matrix\nGot:\n%s\nExpect:\n%s\n", m3, m1)
+	}
+}
+
+func TestMatrixJSONInvalid(t *testing.T) {
+	for i, elem := range []struct {
+		in     string
+		vector bool
+		expect error
+	}{
+		{`{"rows":2,"cols":2,"data":[1,2,3]}`, false, ml.ErrInvalidEncoding},
+		{`{"rows":-1,"cols":2,"data":[]}`, false, ml.ErrInvalidEncoding},
+		{`{"rows":200000000,"cols":0,"data":[]}`, false, ml.ErrInvalidEncoding},
+		{`{"rows":0,"cols":200000000,"data":[]}`, false, ml.ErrInvalidEncoding},
+		{`{"rows":4294967296,"cols":4294967296,"data":[]}`, false, ml.ErrInvalidEncoding},
+		{`[[1,2],[3]]`, false, ml.ErrInconsistentData},
+		{`{"rows":2,"cols":2,"data":[1,2,3,4]}`, true, ml.ErrNotAVector},
+	} {
+		var err error
+		if elem.vector {
+			var v ml.Vector
+			err = json.Unmarshal([]byte(elem.in), &v)
+		} else {
+			var m ml.Matrix
+			err = json.Unmarshal([]byte(elem.in), &m)
+		}
+		if err != elem.expect {
+			t.Errorf("[%d] Unexpected error decoding %s\nExpect:\t%v\nGot:\t%v", i, elem.in, elem.expect, err)
+		}
+	}
+}
+
+func TestMatrixBinary(t *testing.T) {
+	m1 := ml.Matrix{
+		{1, math.Inf(1), 3},
+		{4, 5, -6.5},
+	}
+	buf, err := m1.MarshalBinary()
+	if err != nil {
+		t.Fatalf("Error encoding matrix: %s", err)
+	}
+	if expect, got := 1+4+4+6*8, len(buf); expect != got {
+		t.Fatalf("Unexpected binary size.\nExpect:\t%d\nGot:\t%d", expect, got)
+	}
+	var m2 ml.Matrix
+	if err := m2.UnmarshalBinary(buf); err != nil {
+		t.Fatalf("Error decoding matrix: %s", err)
+	}
+	if !m1.Equal(m2) {
+		t.Fatalf("Unexpected decoded matrix\nGot:\n%s\nExpect:\n%s\n", m2, m1)
+	}
+
+	if err := m2.UnmarshalBinary(buf[:len(buf)-1]); err != ml.ErrInvalidEncoding {
+		t.Fatalf("Unexpected error for truncated buffer: %v", err)
+	}
+	// Shapes without data.
+	for i, shape := range [][2]uint32{{math.MaxUint32, 0}, {0, math.MaxUint32}} {
+		hdr := make([]byte, 9)
+		hdr[0] = buf[0]
+		binary.LittleEndian.PutUint32(hdr[1:], shape[0])
+		binary.LittleEndian.PutUint32(hdr[5:], shape[1])
+		if err := m2.UnmarshalBinary(hdr); err != ml.ErrInvalidEncoding {
+			t.Fatalf("[%d] Unexpected error for a %dx%d header: %v", i, shape[0], shape[1], err)
+		}
+	}
+	buf[0] = 42
+	if err := m2.UnmarshalBinary(buf); err != ml.ErrUnsupportedEncoding {
+		t.Fatalf("Unexpected error for unknown version: %v", err)
+	}
+}
+
+func TestDatasetEncoding(t *testing.T) {
+	ds := ml.Dataset{
+		X: ml.Matrix{{1, 2}, {3, 4}, {5, 6}},
+		Y: ml.Vector{{1}, {0}, {1}},
+	}
+
+	buf, err := json.Marshal(ds)
+	if err != nil {
+		t.Fatalf("Error encoding dataset to JSON: %s", err)
+	}
+	if expect, got := `{"x":{"rows":3,"cols":2,"data":[1,2,3,4,5,6]},"y":{"rows":3,"cols":1,"data":[1,0,1]}}`, string(buf); expect != got {
+		t.Fatalf("Unexpected JSON encoding.\nExpect:\t%s\nGot:\t%s", expect, got)
+	}
+	var ds2 ml.Dataset
+	if err := json.Unmarshal(buf, &ds2); err != nil {
+		t.Fatalf("Error decoding dataset from JSON: %s", err)
+	}
+	if !ds.X.Equal(ds2.X) || !ml.Matrix(ds.Y).Equal(ml.Matrix(ds2.Y)) {
+		t.Fatalf("Unexpected JSON decoded dataset: %v", ds2)
+	}
+
+	var b bytes.Buffer
+	if err := gob.NewEncoder(&b).Encode(ds); err != nil {
+		t.Fatalf("Error encoding dataset to gob: %s", err)
+	}
+	var ds3 ml.Dataset
+	if err := gob.NewDecoder(&b).Decode(&ds3); err != nil {
+		t.Fatalf("Error decoding dataset from gob: %s", err)
+	}
+	if !ds.X.Equal(ds3.X) || !ml.Matrix(ds.Y).Equal(ml.Matrix(ds3.Y)) {
+		t.Fatalf("Unexpected gob decoded dataset: %v", ds3)
+	}
+
+	// Multiple targets per example.
+	ds = ml.Dataset{X: ds.X, Targets: ml.Matrix{{1, 0}, {0, 1}, {1, 0}}}
+	b.Reset()
+	if err := gob.NewEncoder(&b).Encode(ds); err != nil {
+		t.Fatalf("Error encoding dataset targets to gob: %s", err)
+	}
+	var ds4 ml.Dataset
+	if err := gob.NewDecoder(&b).Decode(&ds4); err != nil {
+		t.Fatalf("Error decoding dataset targets from gob: %s", err)
+	}
+	if !ds.Targets.Equal(ds4.Targets) || len(ds4.Y) != 0 {
+		t.Fatalf("Unexpected gob decoded dataset targets: %v", ds4)
+	}
+}
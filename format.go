@@ -0,0 +1,228 @@
+package ml
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Print settings, similar to NumPy's print options.
+var (
+	// PrintThreshold is the element count above which a printed matrix is elided.
+	PrintThreshold = 1000
+	// PrintEdgeItems is the number of rows/cols kept at each edge of an elided matrix.
+	PrintEdgeItems = 3
+)
+
+// ellipsis replaces the elided rows/cols.
+const ellipsis = "…"
+
+// printIndices returns the indices to print out of n, -1 marks the elided part.
+func printIndices(n int, elide bool) []int {
+	ret := make([]int, 0, n)
+	if elide && n > 2*PrintEdgeItems {
+		for i := 0; i < PrintEdgeItems; i++ {
+			ret = append(ret, i)
+		}
+		ret = append(ret, -1)
+		for i := n - PrintEdgeItems; i < n; i++ {
+			ret = append(ret, i)
+		}
+		return ret
+	}
+	for i := 0; i < n; i++ {
+		ret = append(ret, i)
+	}
+	return ret
+}
+
+// cells formats the elements of the matrix with the given element format.
+// Elided rows/cols are set to the ellipsis. Rows shorter than the widest one,
+// as in a matrix failing Validate, get fewer cells.
+func (ma Matrix) cells(elemFmt string, elide bool) [][]string {
+	m, n := len(ma), 0
+	for _, line := range ma {
+		if len(line) > n {
+			n = len(line)
+		}
+	}
+	elide = elide && m*n > PrintThreshold
+	rows, cols := printIndices(m, elide), printIndices(n, elide)
+	ret := make([][]string, len(rows))
+	for i, row := range rows {
+		ret[i] = make([]string, 0, len(cols))
+		for _, col := range cols {
+			switch {
+			case row < 0 || col < 0:
+				ret[i] = append(ret[i], ellipsis)
+			case col < len(ma[row]):
+				ret[i] = append(ret[i], fmt.Sprintf(elemFmt, ma[row][col]))
+			}
+		}
+	}
+	return ret
+}
+
+// format pretty prints the matrix with aligned columns.
+// The elements are formatted with elemFmt, i.e. "%.3f".
+// When elide is set, big matrices only show their edges.
+func (ma Matrix) format(elemFmt string, left, elide bool) string {
+	if ma == nil {
+		return "<nil>"
+	}
+	if len(ma) == 0 {
+		return "||"
+	}
+	cells := ma.cells(elemFmt, elide)
+
+	// Lookup the width of each column.
+	var widths []int
+	for _, line := range cells {
+		for j, cell := range line {
+			if j == len(widths) {
+				widths = append(widths, 0)
+			}
+			if w := utf8.RuneCountInString(cell); w > widths[j] {
+				widths[j] = w
+			}
+		}
+	}
+
+	m, n := ma.Dim()
+	buf := &strings.Builder{}
+	fmt.Fprintf(buf, "(%d,%d)", m, n)
+	for _, line := range cells {
+		buf.WriteString("\n[")
+		for j, cell := range line {
+			if j > 0 {
+				buf.WriteByte(' ')
+			}
+			pad := strings.Repeat(" ", widths[j]-utf8.RuneCountInString(cell))
+			if left {
+				buf.WriteString(cell + pad)
+			} else {
+				buf.WriteString(pad + cell)
+			}
+		}
+		buf.WriteByte(']')
+	}
+	return buf.String()
+}
+
+// Format implements fmt.Formatter.
+//   - %v, %s: aligned matrix, elided when bigger than PrintThreshold.
+//   - %+v: aligned matrix, never elided.
+//   - %#v: Go syntax representation.
+//   - %f, %e, %g (and %F, %E, %G): elements formatted with the given verb,
+//     precision, width and flags, i.e. %.3f, %+8.2e.
+//   - '-' flag: left align the columns.
+func (ma Matrix) Format(f fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if f.Flag('#') {
+			_, _ = io.WriteString(f, ma.goString("ml.Matrix"))
+			return
+		}
+		_, _ = io.WriteString(f, ma.format("%g", f.Flag('-'), !f.Flag('+')))
+	case 's':
+		_, _ = io.WriteString(f, ma.format("%g", f.Flag('-'), true))
+	case 'f', 'F', 'e', 'E', 'g', 'G':
+		elemFmt := "%"
+		for _, flag := range "+ #0" {
+			if f.Flag(int(flag)) {
+				elemFmt += string(flag)
+			}
+		}
+		if w, ok := f.Width(); ok {
+			elemFmt += strconv.Itoa(w)
+		}
+		if p, ok := f.Precision(); ok {
+			elemFmt += "." + strconv.Itoa(p)
+		}
+		elemFmt += string(verb)
+		_, _ = io.WriteString(f, ma.format(elemFmt, f.Flag('-'), true))
+	default:
+		fmt.Fprintf(f, "%%!%c(%s)", verb, ma.String())
+	}
+}
+
+// goString returns the Go syntax representation of the matrix.
+func (ma Matrix) goString(typeName string) string {
+	if ma == nil {
+		return typeName + "(nil)"
+	}
+	buf := &strings.Builder{}
+	buf.WriteString(typeName + "{")
+	for i, line := range ma {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteByte('{')
+		for j, elem := range line {
+			if j > 0 {
+				buf.WriteString(", ")
+			}
+			buf.WriteString(strconv.FormatFloat(elem, 'g', -1, 64))
+		}
+		buf.WriteByte('}')
+	}
+	buf.WriteByte('}')
+	return buf.String()
+}
+
+// Format implements fmt.Formatter, see Matrix.Format.
+func (v Vector) Format(f fmt.State, verb rune) {
+	if verb == 'v' && f.Flag('#') {
+		_, _ = io.WriteString(f, Matrix(v).goString("ml.Vector"))
+		return
+	}
+	Matrix(v).Format(f, verb)
+}
+
+// table returns the formatted cells of the matrix for the table outputs.
+func (ma Matrix) table(elemFmt string) [][]string {
+	if elemFmt == "" {
+		elemFmt = "%g"
+	}
+	return ma.cells(elemFmt, false)
+}
+
+// Markdown renders the matrix as a Markdown table.
+// The elements are formatted with the given format, i.e. "%.3f", defaults to "%g".
+// The header row holds the column numbers.
+func (ma Matrix) Markdown(elemFmt string) string {
+	cells := ma.table(elemFmt)
+	_, n := ma.shape()
+	buf := &strings.Builder{}
+	buf.WriteString("|")
+	for j := 0; j < n; j++ {
+		fmt.Fprintf(buf, " %d |", j+1)
+	}
+	buf.WriteString("\n|")
+	for j := 0; j < n; j++ {
+		buf.WriteString("---:|")
+	}
+	for _, line := range cells {
+		buf.WriteString("\n| " + strings.Join(line, " | ") + " |")
+	}
+	return buf.String()
+}
+
+// LaTeX renders the matrix as a LaTeX bmatrix.
+// The elements are formatted with the given format, i.e. "%.3f", defaults to "%g".
+func (ma Matrix) LaTeX(elemFmt string) string {
+	cells := ma.table(elemFmt)
+	buf := &strings.Builder{}
+	buf.WriteString("\\begin{bmatrix}\n")
+	for i, line := range cells {
+		buf.WriteString(strings.Join(line, " & "))
+		if i < len(cells)-1 {
+			buf.WriteString(" \\\\")
+		}
+		buf.WriteByte('\n')
+	}
+	buf.WriteString("\\end{bmatrix}")
+	return buf.String()
+}
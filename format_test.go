@@ -0,0 +1,82 @@
+package ml_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/creack/ml"
+)
+
+func TestFormat(t *testing.T) {
+	m1 := ml.Matrix{
+		{1, -22.5, 3},
+		{400, 5, 0.125},
+	}
+	for i, elem := range []struct {
+		format string
+		expect string
+	}{
+		{"%v", "(2,3)\n[  1 -22.5     3]\n[400     5 0.125]"},
+		{"%s", "(2,3)\n[  1 -22.5     3]\n[400     5 0.125]"},
+		{"%.2f", "(2,3)\n[  1.00 -22.50 3.00]\n[400.00   5.00 0.12]"},
+		{"%-.1f", "(2,3)\n[1.0   -22.5 3.0]\n[400.0 5.0   0.1]"},
+		{"%+.0f", "(2,3)\n[  +1 -22 +3]\n[+400  +5 +0]"},
+		{"%.1e", "(2,3)\n[1.0e+00 -2.2e+01 3.0e+00]\n[4.0e+02  5.0e+00 1.2e-01]"},
+		{"%#v", "ml.Matrix{{1, -22.5, 3}, {400, 5, 0.125}}"},
+	} {
+		if got := fmt.Sprintf(elem.format, m1); got != elem.expect {
+			t.Errorf("[%d] Unexpected %s formatting.\nExpect:\n%s\nGot:\n%s", i, elem.format, elem.expect, got)
+		}
+	}
+	if expect, got := "ml.Vector{{1}, {2}}", fmt.Sprintf("%#v", ml.Vector{{1}, {2}}); expect != got {
+		t.Errorf("Unexpected vector Go syntax.\nExpect:\t%s\nGot:\t%s", expect, got)
+	}
+	if expect, got := "<nil>", fmt.Sprint(ml.Matrix(nil)); expect != got {
+		t.Errorf("Unexpected nil matrix formatting: %s", got)
+	}
+
+	// Inconsistent matrices still print, to inspect why they fail Validate.
+	m2 := ml.Matrix{{1, 2}, {3}}
+	if expect, got := "(2,2)\n[1 2]\n[3]", m2.String(); expect != got {
+		t.Errorf("Unexpected inconsistent matrix formatting.\nExpect:\n%s\nGot:\n%s", expect, got)
+	}
+	if expect, got := "| 1 | 2 |\n|---:|---:|\n| 1 | 2 |\n| 3 |", m2.Markdown(""); expect != got {
+		t.Errorf("Unexpected inconsistent matrix Markdown.\nExpect:\n%s\nGot:\n%s", expect, got)
+	}
+}
+
+func TestFormatElide(t *testing.T) {
+	m1 := ml.NewMatrix(100, 20)
+	for i := range m1 {
+		for j := range m1[i] {
+			m1[i][j] = float64(i*100 + j)
+		}
+	}
+	lines := strings.Split(m1.String(), "\n")
+	if expect, got := 1+2*ml.PrintEdgeItems+1, len(lines); expect != got {
+		t.Fatalf("Unexpected elided line count.\nExpect:\t%d\nGot:\t%d\n%s", expect, got, m1)
+	}
+	if expect, got := "[   0    1    2 …   17   18   19]", lines[1]; expect != got {
+		t.Fatalf("Unexpected first line.\nExpect:\t%s\nGot:\t%s", expect, got)
+	}
+	if expect, got := "[   … ", lines[4][:len("[   … ")]; expect != got {
+		t.Fatalf("Unexpected elided line.\nExpect:\t%q\nGot:\t%q", expect, got)
+	}
+	if expect, got := 1+100, len(strings.Split(fmt.Sprintf("%+v", m1), "\n")); expect != got {
+		t.Fatalf("Unexpected line count for %%+v.\nExpect:\t%d\nGot:\t%d", expect, got)
+	}
+}
+
+func TestMarkdownLaTeX(t *testing.T) {
+	m1 := ml.Matrix{
+		{1, 2},
+		{3, 4.5},
+	}
+	if expect, got := "| 1 | 2 |\n|---:|---:|\n| 1.0 | 2.0 |\n| 3.0 | 4.5 |", m1.Markdown("%.1f"); expect != got {
+		t.Errorf("Unexpected markdown.\nExpect:\n%s\nGot:\n%s", expect, got)
+	}
+	if expect, got := "\\begin{bmatrix}\n1 & 2 \\\\\n3 & 4.5\n\\end{bmatrix}", m1.LaTeX(""); expect != got {
+		t.Errorf("Unexpected LaTeX.\nExpect:\n%s\nGot:\n%s", expect, got)
+	}
+}
@@ -0,0 +1,38 @@
+package ml
+
+import "math"
+
+// GradientCheck compares the analytic gradient of the cost with its central
+// finite differences (cost(θ+eps) - cost(θ-eps)) / 2eps, component per component.
+// Returns the relative error of each component:
+// $$\frac{|g_j - \tilde{g}_j|}{\max(|g_j|, |\tilde{g}_j|)}$$
+// 0 when both are 0. A correct gradient usually gives errors below 1e-7 with eps = 1e-6.
+// A non-positive eps defaults to 1e-6.
+// NOTE: θ is perturbed during the check and restored before returning, so it can
+// be the model parameters.
+func GradientCheck(cost func(Vector) float64, grad func(Vector) Vector, θ Vector, eps float64) Vector {
+	if eps <= 0 {
+		eps = 1e-6
+	}
+	analytic := grad(θ)
+	if len(analytic) != len(θ) {
+		panic(ErrBadDim)
+	}
+	ret := NewVector(len(θ))
+	for j := range θ {
+		old := θ[j][0]
+		θ[j][0] = old + eps
+		c1 := cost(θ)
+		θ[j][0] = old - eps
+		c2 := cost(θ)
+		θ[j][0] = old
+
+		numeric := (c1 - c2) / (2 * eps)
+		diff := math.Abs(analytic[j][0] - numeric)
+		if scale := math.Max(math.Abs(analytic[j][0]), math.Abs(numeric)); scale > 0 {
+			diff /= scale
+		}
+		ret[j][0] = diff
+	}
+	return ret
+}
@@ -0,0 +1,93 @@
+package ml_test
+
+import (
+	"testing"
+
+	"github.com/creack/ml"
+)
+
+// gradientCheckTolerance is the max relative error accepted for a correct gradient.
+const gradientCheckTolerance = 1e-6
+
+// partialGradient builds the gradient vector from a partial derivative function.
+func partialGradient(partial func(θ ml.Vector, j int) float64) func(ml.Vector) ml.Vector {
+	return func(θ ml.Vector) ml.Vector {
+		ret := ml.NewVector(len(θ))
+		for j := range θ {
+			ret[j][0] = partial(θ, j)
+		}
+		return ret
+	}
+}
+
+func assertGradient(t *testing.T, name string, errs ml.Vector) {
+	t.Helper()
+	for j, elem := range errs {
+		if elem[0] > gradientCheckTolerance {
+			t.Errorf("%s: relative error too high for Θ[%d]: %g", name, j, elem[0])
+		}
+	}
+}
+
+func TestGradientCheckLinearRegression(t *testing.T) {
+	for _, elem := range []struct {
+		name    string
+		lambda  float64
+		l1Ratio float64
+	}{
+		{"plain", 0, 0},
+		{"ridge", 0.5, 0},
+		{"lasso", 0.5, 1},
+		{"elastic net", 0.5, 0.3},
+	} {
+		cost := func(θ ml.Vector) float64 {
+			return ml.LinearRegression{Θ: θ, Lambda: elem.lambda, L1Ratio: elem.l1Ratio}.SquaredError(testRegularizationDataset)
+		}
+		grad := partialGradient(func(θ ml.Vector, j int) float64 {
+			return ml.LinearRegression{Θ: θ, Lambda: elem.lambda, L1Ratio: elem.l1Ratio}.PartialDerivative(testRegularizationDataset, j)
+		})
+		_, n := testRegularizationDataset.X.Dim()
+		θ := ml.NewVector(n + 1)
+		for j := range θ {
+			θ[j][0] = 0.3*float64(j) - 0.4
+		}
+		assertGradient(t, elem.name, ml.GradientCheck(cost, grad, θ, 1e-6))
+	}
+}
+
+func TestGradientCheckLogisticRegression(t *testing.T) {
+	cost := func(θ ml.Vector) float64 {
+		return ml.LogisticRegression{Θ: θ}.Cost(testLogisticDataset)
+	}
+	grad := partialGradient(func(θ ml.Vector, j int) float64 {
+		return ml.LogisticRegression{Θ: θ}.PartialDerivative(testLogisticDataset, j)
+	})
+	assertGradient(t, "logistic", ml.GradientCheck(cost, grad, ml.Vector{{-1}, {0.5}}, 0))
+}
+
+func TestGradientCheckDetectsBug(t *testing.T) {
+	cost := func(θ ml.Vector) float64 {
+		return ml.LinearRegression{Θ: θ}.SquaredError(testRegularizationDataset)
+	}
+	grad := partialGradient(func(θ ml.Vector, j int) float64 {
+		d := ml.LinearRegression{Θ: θ}.PartialDerivative(testRegularizationDataset, j)
+		if j == 1 {
+			d *= 2 // Bogus derivative.
+		}
+		return d
+	})
+	_, n := testRegularizationDataset.X.Dim()
+	θ := ml.NewVector(n + 1)
+	θ[0][0] = 1
+	errs := ml.GradientCheck(cost, grad, θ, 1e-6)
+	if errs[1][0] < 0.1 {
+		t.Fatalf("Bogus derivative not detected: %g", errs[1][0])
+	}
+	if errs[0][0] > gradientCheckTolerance {
+		t.Fatalf("Unexpected relative error for Θ[0]: %g", errs[0][0])
+	}
+	// θ is restored.
+	if θ[0][0] != 1 || θ[1][0] != 0 {
+		t.Fatalf("θ not restored after the check: %v", θ)
+	}
+}
@@ -0,0 +1,256 @@
+package ml
+
+import (
+	"context"
+	"errors"
+	"math"
+)
+
+// ErrNoLearningRate is returned when scheduling the learning rate of an optimizer
+// without one.
+var ErrNoLearningRate = errors.New("the optimizer has no learning rate to schedule")
+
+// ScheduledOptimizer is an optimizer with a learning rate, see TrainOptions.Schedule.
+type ScheduledOptimizer interface {
+	Optimizer
+	SetLearningRate(alpha float64)
+}
+
+// SetLearningRate implements ScheduledOptimizer.
+func (o *SGD) SetLearningRate(alpha float64) { o.Alpha = alpha }
+
+// SetLearningRate implements ScheduledOptimizer.
+func (o *Momentum) SetLearningRate(alpha float64) { o.Alpha = alpha }
+
+// SetLearningRate implements ScheduledOptimizer.
+func (o *Nesterov) SetLearningRate(alpha float64) { o.Alpha = alpha }
+
+// SetLearningRate implements ScheduledOptimizer.
+func (o *AdaGrad) SetLearningRate(alpha float64) { o.Alpha = alpha }
+
+// SetLearningRate implements ScheduledOptimizer.
+func (o *RMSProp) SetLearningRate(alpha float64) { o.Alpha = alpha }
+
+// SetLearningRate implements ScheduledOptimizer.
+func (o *Adam) SetLearningRate(alpha float64) { o.Alpha = alpha }
+
+// Schedule gives the learning rate of each iteration.
+type Schedule interface {
+	Rate(iteration int) float64 // Learning rate of the given iteration, starting at 0.
+}
+
+// ScheduleFunc is a function implementing Schedule.
+type ScheduleFunc func(iteration int) float64
+
+// Rate implements Schedule.
+func (f ScheduleFunc) Rate(iteration int) float64 {
+	return f(iteration)
+}
+
+// StepDecay multiplies the rate by Factor every given number of iterations:
+// α = α0 * factor^⌊t/every⌋.
+type StepDecay struct {
+	Initial float64
+	Factor  float64 // i.e. 0.5.
+	Every   int
+}
+
+// Rate implements Schedule.
+func (s StepDecay) Rate(iteration int) float64 {
+	if s.Every <= 0 {
+		return s.Initial
+	}
+	return s.Initial * math.Pow(s.Factor, float64(iteration/s.Every))
+}
+
+// ExponentialDecay decays the rate at each iteration: α = α0 * decay^t.
+type ExponentialDecay struct {
+	Initial float64
+	Decay   float64 // i.e. 0.99. Greater than 1 grows the rate.
+}
+
+// Rate implements Schedule.
+func (s ExponentialDecay) Rate(iteration int) float64 {
+	return s.Initial * math.Pow(s.Decay, float64(iteration))
+}
+
+// InverseTimeDecay decays the rate as 1/t: α = α0 / (1 + decay * t).
+type InverseTimeDecay struct {
+	Initial float64
+	Decay   float64
+}
+
+// Rate implements Schedule.
+func (s InverseTimeDecay) Rate(iteration int) float64 {
+	return s.Initial / (1 + s.Decay*float64(iteration))
+}
+
+// CosineAnnealing anneals the rate from Initial to Min over Period iterations
+// following a half cosine, then stays at Min:
+// α = min + (α0 - min) * (1 + cos(π * t / period)) / 2.
+type CosineAnnealing struct {
+	Initial float64
+	Min     float64
+	Period  int
+}
+
+// Rate implements Schedule.
+func (s CosineAnnealing) Rate(iteration int) float64 {
+	if iteration >= s.Period {
+		return s.Min
+	}
+	return cosineRate(s.Initial, s.Min, iteration, s.Period)
+}
+
+// WarmRestarts is cosine annealing restarting from Initial at the end of each
+// period (SGDR). Each period is Mult times longer than the previous one.
+type WarmRestarts struct {
+	Initial float64
+	Min     float64
+	Period  int     // First period.
+	Mult    float64 // Period growth, defaults to 1.
+}
+
+// Rate implements Schedule.
+func (s WarmRestarts) Rate(iteration int) float64 {
+	if s.Period <= 0 {
+		return s.Initial
+	}
+	mult := defaultFloat(s.Mult, 1)
+	period := s.Period
+	for iteration >= period {
+		iteration -= period
+		period = int(math.Max(1, math.Round(float64(period)*mult)))
+	}
+	return cosineRate(s.Initial, s.Min, iteration, period)
+}
+
+func cosineRate(initial, min float64, iteration, period int) float64 {
+	return min + (initial-min)*(1+math.Cos(math.Pi*float64(iteration)/float64(period)))/2
+}
+
+// costOptimizer is an optimizer evaluating the cost. Train sets the cost of
+// the current batch before each step.
+type costOptimizer interface {
+	setCost(cost func() float64)
+}
+
+// Backtracking is gradient descent with the step chosen automatically by a
+// backtracking line search: starting from twice the last accepted step, the step is
+// shrunk until the Armijo sufficient decrease condition
+// cost(θ - α∇) <= cost(θ) - c1 * α * ||∇||² holds.
+// Only usable with Train, which provides the cost.
+type Backtracking struct {
+	Initial float64 // First step tried, defaults to 1.
+	Shrink  float64 // Step reduction factor, defaults to 0.5.
+	C1      float64 // Sufficient decrease parameter, defaults to 1e-4.
+
+	Alpha float64 // State, last accepted step.
+
+	cost func() float64
+}
+
+func (o *Backtracking) setCost(cost func() float64) {
+	o.cost = cost
+}
+
+// Step implements Optimizer.
+// Leaves θ unchanged if no decreasing step is found.
+func (o *Backtracking) Step(θ, grad Vector) {
+	shrink, c1 := defaultFloat(o.Shrink, 0.5), defaultFloat(o.C1, 1e-4)
+	α := 2 * o.Alpha
+	if α == 0 {
+		α = defaultFloat(o.Initial, 1)
+	}
+	x0 := Matrix(θ).Copy()
+	f0, g2 := o.cost(), dot(grad, grad)
+	for i := 0; i < 60; i++ {
+		for j := range θ {
+			θ[j][0] = x0[j][0] - α*grad[j][0]
+		}
+		if f := o.cost(); isFinite(f) && f <= f0-c1*α*g2 {
+			o.Alpha = α
+			return
+		}
+		α *= shrink
+	}
+	Matrix(θ).SetSubMatrix(x0, 0, 0)
+}
+
+// LRRangeOptions configures LRRangeTest.
+type LRRangeOptions struct {
+	Min, Max   float64 // Learning rate range, defaults to [1e-6, 10].
+	Iterations int     // Number of rates tried, defaults to 100.
+	BatchSize  int     // See TrainOptions.
+	Rand       *RNG    // See TrainOptions.
+}
+
+// LRRangeResult reports a learning rate range test.
+type LRRangeResult struct {
+	Rates     []float64 // Learning rate of each iteration.
+	Costs     []float64 // Cost after each iteration.
+	Suggested float64   // Rate with the steepest cost decrease.
+}
+
+// LRRangeTest runs the learning rate range test: trains the model with a learning
+// rate growing exponentially from opts.Min to opts.Max, one iteration each, and
+// records the cost. The test stops early when the cost diverges (4 times the best cost).
+// A good learning rate is usually found where the cost decreases the fastest.
+// The model parameters are restored at the end, the optimizer state is not.
+func LRRangeTest(ctx context.Context, model Model, dataset Dataset, opt ScheduledOptimizer, opts LRRangeOptions) (LRRangeResult, error) {
+	min, max := defaultFloat(opts.Min, 1e-6), defaultFloat(opts.Max, 10)
+	iterations := opts.Iterations
+	if iterations <= 0 {
+		iterations = 100
+	}
+	growth := 1.
+	if iterations > 1 {
+		growth = math.Pow(max/min, 1/float64(iterations-1))
+	}
+	schedule := ExponentialDecay{Initial: min, Decay: growth}
+
+	θ := model.Parameters()
+	initial := Matrix(θ).Copy()
+	defer Matrix(θ).SetSubMatrix(initial, 0, 0)
+
+	// Cancel the training once the cost diverges.
+	ctx1, cancel := context.WithCancel(ctx)
+	defer cancel()
+	best := math.Inf(1)
+	diverged := ObserverFunc(func(e Event) {
+		best = math.Min(best, e.Cost)
+		if e.Cost > 4*best {
+			cancel()
+		}
+	})
+
+	var ret LRRangeResult
+	res, err := Train(ctx1, model, dataset, opt, TrainOptions{
+		MaxIterations: iterations,
+		BatchSize:     opts.BatchSize,
+		Rand:          opts.Rand,
+		Schedule:      schedule,
+		Observers:     []Observer{diverged},
+	})
+	if ctx.Err() != nil {
+		return ret, ctx.Err()
+	}
+	// Divergence is expected with the largest rates, not before the first iteration.
+	var nf *NonFiniteError
+	if err != nil && (len(res.History) < 2 || (!errors.Is(err, context.Canceled) && !errors.As(err, &nf))) {
+		return ret, err
+	}
+	for i, c := range res.History[1:] {
+		ret.Rates = append(ret.Rates, schedule.Rate(i))
+		ret.Costs = append(ret.Costs, c)
+	}
+
+	// Steepest decrease of the cost against log(α).
+	steepest := 0.
+	for i := 1; i < len(ret.Costs); i++ {
+		if d := ret.Costs[i] - ret.Costs[i-1]; d < steepest {
+			steepest, ret.Suggested = d, ret.Rates[i]
+		}
+	}
+	return ret, nil
+}
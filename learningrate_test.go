@@ -0,0 +1,110 @@
+package ml_test
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/creack/ml"
+)
+
+func TestSchedules(t *testing.T) {
+	for i, elem := range []struct {
+		schedule ml.Schedule
+		expect   []float64 // Rates of iterations 0 to len-1.
+	}{
+		{ml.StepDecay{Initial: 1, Factor: 0.5, Every: 2}, []float64{1, 1, 0.5, 0.5, 0.25}},
+		{ml.ExponentialDecay{Initial: 2, Decay: 0.5}, []float64{2, 1, 0.5, 0.25}},
+		{ml.InverseTimeDecay{Initial: 1, Decay: 1}, []float64{1, 0.5, 1. / 3, 0.25}},
+		{ml.CosineAnnealing{Initial: 1, Min: 0, Period: 4}, []float64{1, (1 + math.Sqrt2/2) / 2, 0.5, (1 - math.Sqrt2/2) / 2, 0, 0}},
+		{ml.WarmRestarts{Initial: 1, Min: 0, Period: 2}, []float64{1, 0.5, 1, 0.5, 1}},
+		{ml.WarmRestarts{Initial: 1, Min: 0, Period: 2, Mult: 2}, []float64{1, 0.5, 1, (1 + math.Sqrt2/2) / 2, 0.5, (1 - math.Sqrt2/2) / 2, 1}},
+		{ml.ScheduleFunc(func(t int) float64 { return float64(t) }), []float64{0, 1, 2}},
+	} {
+		for t1, expect := range elem.expect {
+			if got := elem.schedule.Rate(t1); stringify(expect) != stringify(got) {
+				t.Errorf("[%d] Unexpected rate for iteration %d.\nExpect:\t%g\nGot:\t%g", i, t1, expect, got)
+			}
+		}
+	}
+}
+
+func TestTrainSchedule(t *testing.T) {
+	var rates []float64
+	opt := &ml.Momentum{Beta: 0.5}
+	observer := ml.ObserverFunc(func(ml.Event) { rates = append(rates, opt.Alpha) })
+	opts := ml.TrainOptions{MaxIterations: 3, Schedule: ml.ExponentialDecay{Initial: 0.1, Decay: 0.1}, Observers: []ml.Observer{observer}}
+	lr := &ml.LinearRegression{Θ: ml.NewVector(3)}
+	if _, err := ml.Train(context.Background(), lr, testRegularizationDataset, opt, opts); err != nil {
+		t.Fatalf("Error training the model: %s", err)
+	}
+	for i, expect := range []float64{0.1, 0.01, 0.001} {
+		if stringify(rates[i]) != stringify(expect) {
+			t.Fatalf("Unexpected learning rates: %v", rates)
+		}
+	}
+
+	opts.Schedule = ml.StepDecay{Initial: 1}
+	if _, err := ml.Train(context.Background(), lr, testRegularizationDataset, &ml.Backtracking{}, opts); !errors.Is(err, ml.ErrNoLearningRate) {
+		t.Fatalf("Unexpected error scheduling an optimizer without learning rate: %v", err)
+	}
+}
+
+func TestBacktracking(t *testing.T) {
+	optimum := &ml.LinearRegression{Θ: ml.NewVector(3)}
+	if err := optimum.NormalEquation(testRegularizationDataset); err != nil {
+		t.Fatalf("Error solving the normal equation: %s", err)
+	}
+	// A first step way too large, shrunk by the line search.
+	opt := &ml.Backtracking{Initial: 1e6}
+	lr := &ml.LinearRegression{Θ: ml.NewVector(3)}
+	res, err := ml.Train(context.Background(), lr, testRegularizationDataset, opt, ml.TrainOptions{MaxIterations: 1e5, GradientTolerance: 1e-6})
+	if err != nil {
+		t.Fatalf("Error training the model: %s", err)
+	}
+	if res.Reason != ml.TerminationGradientTolerance {
+		t.Fatalf("Unexpected termination: %s", res.Reason)
+	}
+	if !approxEqual(ml.Matrix(lr.Θ), ml.Matrix(optimum.Θ), 1e-4) {
+		t.Fatalf("Unexpected Θ.\nExpect:\t%v\nGot:\t%v", optimum.Θ, lr.Θ)
+	}
+	for i := 1; i < len(res.History); i++ {
+		if res.History[i] > res.History[i-1] {
+			t.Fatalf("Cost increased at iteration %d: %g > %g", i, res.History[i], res.History[i-1])
+		}
+	}
+	if opt.Alpha <= 0 || opt.Alpha >= 1e6 {
+		t.Fatalf("Unexpected accepted step: %g", opt.Alpha)
+	}
+}
+
+func TestLRRangeTest(t *testing.T) {
+	lr := &ml.LinearRegression{Θ: ml.NewVector(3)}
+	res, err := ml.LRRangeTest(context.Background(), lr, testRegularizationDataset, &ml.SGD{}, ml.LRRangeOptions{Min: 1e-5, Max: 10, Iterations: 60})
+	if err != nil {
+		t.Fatalf("Error running the range test: %s", err)
+	}
+	// SGD diverges well before α = 10 on this dataset.
+	if n := len(res.Costs); n == 0 || n >= 60 || n != len(res.Rates) {
+		t.Fatalf("Unexpected number of rates tried: %d", n)
+	}
+	if res.Rates[0] != 1e-5 || res.Suggested <= 1e-5 || res.Suggested >= 1 {
+		t.Fatalf("Unexpected rates: first %g, suggested %g", res.Rates[0], res.Suggested)
+	}
+	if !ml.Matrix(lr.Θ).Equal(ml.NewMatrix(3, 1)) {
+		t.Fatalf("Parameters not restored: %v", lr.Θ)
+	}
+
+	// The suggested rate trains the model.
+	if _, err := ml.Train(context.Background(), lr, testRegularizationDataset, &ml.SGD{Alpha: res.Suggested}, ml.TrainOptions{}); err != nil {
+		t.Fatalf("Error training with the suggested rate %g: %s", res.Suggested, err)
+	}
+
+	// A non finite initial cost is an error, not a divergence.
+	lr = &ml.LinearRegression{Θ: ml.Vector{{math.Inf(1)}, {0}, {0}}}
+	var nf *ml.NonFiniteError
+	if _, err := ml.LRRangeTest(context.Background(), lr, testRegularizationDataset, &ml.SGD{}, ml.LRRangeOptions{}); !errors.As(err, &nf) {
+		t.Fatalf("Unexpected error for a non finite initial cost: %v", err)
+	}
+}
@@ -0,0 +1,138 @@
+package ml
+
+import (
+	"context"
+	"fmt"
+	"math"
+)
+
+// Sigmoid is the logistic function: 1 / (1 + e^-z).
+func Sigmoid(z float64) float64 {
+	if z >= 0 {
+		return 1 / (1 + math.Exp(-z))
+	}
+	// Avoid overflowing e^-z for large negative z.
+	e := math.Exp(z)
+	return e / (1 + e)
+}
+
+// softplus returns log(1 + e^z) without overflow.
+func softplus(z float64) float64 {
+	return math.Max(z, 0) + math.Log1p(math.Exp(-math.Abs(z)))
+}
+
+// LogisticRegression is the logistic regression binary classifier.
+//   - Hypothesis: h(x) = sigmoid(Θ^T * x), the probability of y = 1.
+//
+// The dataset labels are expected to be 0 or 1.
+type LogisticRegression struct {
+	Θ Vector
+}
+
+// Fct implements the hypothesis function.
+func (b LogisticRegression) Fct(x Vector) float64 {
+	return Sigmoid(b.Θ.T().Mul(Matrix(x).View())[0][0])
+}
+
+// z returns Θ^T * x for the given dataset row.
+func (b LogisticRegression) z(row MRow) float64 {
+	return b.Θ.T().Mul(Matrix{row}.T())[0][0]
+}
+
+// PredictProba returns the probability of y = 1 for the given features.
+// The x(0) = 1 bias is added if x does not already have it.
+func (b LogisticRegression) PredictProba(x Vector) float64 {
+	if len(x) == len(b.Θ)-1 {
+		x = Vector(Matrix(x).Extend(1, 0))
+		copy(x[1:], x[:len(x)-1])
+		x[0] = MRow{1}
+	}
+	return b.Fct(x)
+}
+
+// Predict returns the predicted label (0 or 1) for the given features.
+// The label is 1 when the probability reaches the given threshold.
+func (b LogisticRegression) Predict(x Vector, threshold float64) float64 {
+	if b.PredictProba(x) >= threshold {
+		return 1
+	}
+	return 0
+}
+
+// SquaredError process the squared error of the predicted probabilities
+// on the given dataset. Implements the Hypothesis interface, the model
+// is trained on Cost.
+// $$\frac{1}{2m}\sum_{i=1}^{m} (h(x^{(i)})-y^{(i)})^2 $$
+func (b LogisticRegression) SquaredError(dataset Dataset) float64 {
+	dataset = dataset.withBias(len(b.Θ))
+	m, _ := dataset.X.Dim()
+	var sum Accumulator
+	for i := 0; i < m; i++ {
+		tmp := Sigmoid(b.z(dataset.X[i])) - dataset.Y[i][0]
+		sum.Add(tmp * tmp)
+	}
+	return checkValue("SquaredError", (1/(2*float64(m)))*sum.Sum())
+}
+
+// Cost process the cross-entropy cost of the hypothesis on the given dataset.
+// $$-\frac{1}{m}\sum_{i=1}^{m} y^{(i)}\log(h(x^{(i)})) + (1-y^{(i)})\log(1-h(x^{(i)}))$$
+// Computed as 1/m * Sum of softplus(z) - y*z with z = Θ^T * x, which
+// does not overflow for large |z|.
+func (b LogisticRegression) Cost(dataset Dataset) float64 {
+	dataset = dataset.withBias(len(b.Θ))
+	m, _ := dataset.X.Dim()
+	var sum Accumulator
+	for i := 0; i < m; i++ {
+		z := b.z(dataset.X[i])
+		sum.Add(softplus(z) - dataset.Y[i][0]*z)
+	}
+	return checkValue("Cost", (1/float64(m))*sum.Sum())
+}
+
+// PartialDerivative returns the partial derivative of the cost for Θ[j].
+// $$\frac{1}{m}\sum_{i=1}^{m} (h(x^{(i)})-y^{(i)})x_j^{(i)}$$
+func (b LogisticRegression) PartialDerivative(dataset Dataset, j int) float64 {
+	dataset = dataset.withBias(len(b.Θ))
+	m, _ := dataset.X.Dim()
+	var sum Accumulator
+	for i := 0; i < m; i++ {
+		tmp := Sigmoid(b.z(dataset.X[i])) - dataset.Y[i][0]
+		sum.Add(tmp * dataset.X[i][j])
+	}
+	return checkValue("PartialDerivative", (1/float64(m))*sum.Sum())
+}
+
+// Parameters implements Model.
+func (b LogisticRegression) Parameters() Vector {
+	return b.Θ
+}
+
+// Gradient implements Model, the partial derivative for each Θ component.
+// Processed in a single pass over the dataset.
+func (b LogisticRegression) Gradient(dataset Dataset) Vector {
+	dataset = dataset.withBias(len(b.Θ))
+	m, _ := dataset.X.Dim()
+	sums := make([]Accumulator, len(b.Θ))
+	for i := 0; i < m; i++ {
+		tmp := Sigmoid(b.z(dataset.X[i])) - dataset.Y[i][0]
+		for j, x := range dataset.X[i] {
+			sums[j].Add(tmp * x)
+		}
+	}
+	ret := NewVector(len(b.Θ))
+	for j := range ret {
+		ret[j][0] = checkValue("Gradient", (1/float64(m))*sums[j].Sum())
+	}
+	return ret
+}
+
+// GradientDescent trains the model on the cross-entropy cost with batch
+// gradient descent until convergence or until the context is done.
+// Same behavior as LinearRegression.GradientDescent.
+func (b *LogisticRegression) GradientDescent(ctx context.Context, dataset Dataset, alpha float64, opts TrainOptions) (TrainResult, error) {
+	return Train(ctx, b, dataset.withBias(len(b.Θ)), &SGD{Alpha: alpha}, opts)
+}
+
+func (b LogisticRegression) String() string {
+	return fmt.Sprintf("Θ: %v", b.Θ)
+}
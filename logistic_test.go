@@ -0,0 +1,94 @@
+package ml_test
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/creack/ml"
+)
+
+var testLogisticDataset = ml.Dataset{
+	X: ml.Matrix{
+		{1},
+		{2},
+		{3},
+		{4},
+		{5},
+		{6},
+	},
+	Y: ml.Vector{
+		{0},
+		{0},
+		{1},
+		{0},
+		{1},
+		{1},
+	},
+}
+
+func TestSigmoid(t *testing.T) {
+	for i, elem := range []struct {
+		z, expect float64
+	}{
+		{0, 0.5},
+		{math.Log(3), 0.75},
+		{-math.Log(3), 0.25},
+		{1000, 1},
+		{-1000, 0},
+	} {
+		if expect, got := stringify(elem.expect), stringify(ml.Sigmoid(elem.z)); expect != got {
+			t.Errorf("[%d] Unexpected sigmoid(%g).\nExpect:\t%s\nGot:\t%s", i, elem.z, expect, got)
+		}
+	}
+}
+
+func TestLogisticCost(t *testing.T) {
+	lr := ml.LogisticRegression{Θ: ml.Vector{{0}, {0}}}
+	if expect, got := stringify(math.Log(2)), stringify(lr.Cost(testLogisticDataset)); expect != got {
+		t.Fatalf("Unexpected cost for Θ = 0.\nExpect:\t%s\nGot:\t%s", expect, got)
+	}
+	if expect, got := stringify(0.125), stringify(lr.SquaredError(testLogisticDataset)); expect != got {
+		t.Fatalf("Unexpected squared error for Θ = 0.\nExpect:\t%s\nGot:\t%s", expect, got)
+	}
+	// Large |z| must not overflow.
+	lr = ml.LogisticRegression{Θ: ml.Vector{{0}, {1000}}}
+	if cost := lr.Cost(testLogisticDataset); math.IsInf(cost, 0) || math.IsNaN(cost) {
+		t.Fatalf("Cost overflowed for large Θ: %g", cost)
+	}
+}
+
+func TestLogisticGradientDescent(t *testing.T) {
+	lr := &ml.LogisticRegression{Θ: ml.Vector{{0}, {0}}}
+	initial := lr.Cost(testLogisticDataset)
+	if _, err := lr.GradientDescent(context.Background(), testLogisticDataset, 0.5, ml.GradientDescentOptions()); err != nil {
+		t.Fatalf("Error training the model: %s", err)
+	}
+
+	if cost := lr.Cost(testLogisticDataset); cost >= initial {
+		t.Fatalf("Cost did not decrease: %g >= %g", cost, initial)
+	}
+	// At the optimum, the gradient is 0.
+	for j := range lr.Θ {
+		if d := lr.PartialDerivative(testLogisticDataset, j); math.Abs(d) > 1e-9 {
+			t.Fatalf("Non zero partial derivative for Θ[%d] after training: %g", j, d)
+		}
+	}
+	for i, elem := range []struct {
+		x      float64
+		expect float64
+	}{
+		{0, 0},
+		{1, 0},
+		{6, 1},
+		{10, 1},
+	} {
+		if got := lr.Predict(ml.Vector{{elem.x}}, 0.5); got != elem.expect {
+			t.Errorf("[%d] Unexpected prediction for %g: %g (p=%g)", i, elem.x, got, lr.PredictProba(ml.Vector{{elem.x}}))
+		}
+	}
+	// The decision boundary is at x = 3.5 by symmetry of the dataset.
+	if expect, got := stringify(0.5), stringify(lr.PredictProba(ml.Vector{{3.5}})); expect != got {
+		t.Fatalf("Unexpected probability at the decision boundary.\nExpect:\t%s\nGot:\t%s", expect, got)
+	}
+}
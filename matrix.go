@@ -2,9 +2,7 @@ package ml
 
 import (
 	"errors"
-	"fmt"
 	"math"
-	"strings"
 )
 
 // Common erros.
@@ -17,6 +15,8 @@ var (
 	ErrOutOfBound          = errors.New("index out of bound")
 	ErrNotAVector          = errors.New("the current vector has invalid dimension for a vector")
 	ErrSingularMatrix      = errors.New("the matrix is singuler")
+	ErrNotSPD              = errors.New("the matrix is not symmetric positive definite")
+	ErrNoConvergence       = errors.New("the iteration did not converge")
 )
 
 // MRow is the row type for matrix.
@@ -89,7 +89,7 @@ func (ma Matrix) Add(ma2 Matrix) Matrix {
 			ret[i][j] = ma[i][j] + ma2[i][j]
 		}
 	}
-	return ret
+	return ret.check("Add")
 }
 
 // Sub substracts the given matrix to the current one and return the result.
@@ -107,31 +107,31 @@ func (ma Matrix) Sub(ma2 Matrix) Matrix {
 			ret[i][j] = ma[i][j] - ma2[i][j]
 		}
 	}
-	return ret
+	return ret.check("Sub")
 }
 
 // Mul returns the result of the current matrix multiplied by the given one.
 // NOTE: Does not change current matrix state.
 func (ma Matrix) Mul(ma2 Matrix) Matrix {
-	_, n1 := ma.Dim()
-	m2, _ := ma2.Dim()
+	m1, n1 := ma.Dim()
+	m2, n2 := ma2.Dim()
 	if n1 != m2 {
 		panic(ErrBadDim)
 	}
-	ret := NewMatrix(ma.Dim())
+	ret := NewMatrix(m1, n2)
 	for i := range ma {
 		if len(ma[i]) == 0 {
 			continue
 		}
 		for j := range ma2[0] {
-			sum := 0.
+			var sum Accumulator
 			for k := range ma[0] {
-				sum += ma[i][k] * ma2[k][j]
+				sum.Add(ma[i][k] * ma2[k][j])
 			}
-			ret[i][j] = sum
+			ret[i][j] = sum.Sum()
 		}
 	}
-	return ret
+	return ret.check("Mul")
 }
 
 // Scale returns the result of the scalar multiplication of the given scalar
@@ -147,7 +147,7 @@ func (ma Matrix) Scale(n float64) Matrix {
 			ret[i][j] = ma[i][j] * n
 		}
 	}
-	return ret
+	return ret.check("Scale")
 }
 
 // MulV multiplies the current matrix with the given vector.
@@ -184,37 +184,44 @@ func (ma Matrix) Inverse() Matrix {
 	ret := ma.Extend(0, n) // Add 0 rows and n cols.
 
 	// Step 2: Set the right half of the matrix as the identity matrix.
-	ret = ret.SetSubMatrix(ret.SubMatrix(0, n, m, n).Identity(), 0, n) // sub matrix starts at (0,n) and has a (m,n) size.
+	for i := 0; i < m; i++ {
+		ret[i][n+i] = 1
+	}
 
+	// Step 3: Gauss-Jordan elimination, rows are updated in place.
 	for i := 0; i < len(ret); i++ {
 		if len(ma[i]) == 0 {
 			continue
 		}
 		j := i
 		for k := i; k < len(ret); k++ {
-			if math.Abs(ret[k][j]) > math.Abs(ret[j][i]) {
+			if math.Abs(ret[k][i]) > math.Abs(ret[j][i]) {
 				j = k
 			}
 		}
 		if j != i {
 			// Swap rows.
-			tmp := ret[i]
-			ret[i] = ret[j]
-			ret[j] = tmp
+			ret[i], ret[j] = ret[j], ret[i]
 		}
 		if ret[i][i] == 0 {
 			panic(ErrSingularMatrix)
 		}
 		// Inverse the i'th row.
-		ret[i] = ret[i].Scale(1 / ret[i][i])
+		pivot := 1 / ret[i][i]
+		for c := range ret[i] {
+			ret[i][c] *= pivot
+		}
 		for k := 0; k < n; k++ {
-			if k == i {
+			if k == i || ret[k][i] == 0 {
 				continue
 			}
-			ret[k] = ret[k].Add(ret[i].Scale(-ret[k][i]))
+			f := ret[k][i]
+			for c := range ret[k] {
+				ret[k][c] -= f * ret[i][c]
+			}
 		}
 	}
-	return ret.SubMatrix(0, n, m, n)
+	return ret.SubMatrix(0, n, m, n).check("Inverse")
 }
 
 // Identity returns the identify matrix for the current one.
@@ -364,19 +371,9 @@ func (ma Matrix) SetSubMatrix(ma2 Matrix, m, n int) Matrix {
 // }
 
 // String pretty prints the matrix.
+// Columns are aligned and big matrices are elided, see Format for more options.
 func (ma Matrix) String() string {
-	if ma == nil {
-		return "<nil>"
-	}
-	if len(ma) == 0 {
-		return "||"
-	}
-	m, n := ma.Dim()
-	ret := fmt.Sprintf("(%d,%d)\n", m, n)
-	for _, line := range ma {
-		ret += fmt.Sprintf("%4v\n", line)
-	}
-	return strings.TrimSpace(ret)
+	return ma.format("%g", false, true)
 }
 
 // Vector is a matrix with 1 column.
@@ -422,12 +419,15 @@ func (v Vector) Transpose() Matrix {
 }
 
 // Sum computes the sum of all the vector elements.
+// Uses compensated summation, see Accumulator.
 func (v Vector) Sum() float64 {
-	sum := 0.0
-	for _, elem := range v[0] {
-		sum += elem
+	var sum Accumulator
+	for _, line := range v {
+		for _, elem := range line {
+			sum.Add(elem)
+		}
 	}
-	return sum
+	return checkValue("Sum", sum.Sum())
 }
 
 // SubV returns the result of v - v2 as a copy.
@@ -0,0 +1,69 @@
+package ml
+
+// Kron returns the Kronecker product of the current matrix and the given one.
+// For a (m1,n1) and a (m2,n2) matrix, the result is of dimension (m1*m2,n1*n2)
+// and made of the (m2,n2) blocks ma[i][j]*ma2.
+// NOTE: Does not change current matrix state.
+func (ma Matrix) Kron(ma2 Matrix) Matrix {
+	m1, n1 := ma.Dim()
+	m2, n2 := ma2.Dim()
+	ret := NewMatrix(m1*m2, n1*n2)
+	for i, line := range ma {
+		for j, elem := range line {
+			ret.SetSubMatrix(ma2.Scale(elem), i*m2, j*n2)
+		}
+	}
+	return ret
+}
+
+// Outer returns the outer product of the two given vectors: v1 * v2^T.
+// For vectors of dimension n and m, the result is a (n,m) matrix.
+func Outer(v1, v2 Vector) Matrix {
+	return Matrix(v1).Mul(v2.Transpose())
+}
+
+// Block assembles a matrix from the given grid of sub matrices.
+// All the blocks of a grid row need to have the same number of rows
+// and all the blocks of a grid column the same number of columns.
+// panic with ErrBadDim if the blocks do not line up.
+func Block(blocks [][]Matrix) Matrix {
+	if len(blocks) == 0 {
+		return Matrix{}
+	}
+	// Lookup the dimension of each grid row/col and make sure they line up.
+	heights := make([]int, len(blocks))
+	widths := make([]int, len(blocks[0]))
+	for i, line := range blocks {
+		if len(line) != len(widths) {
+			panic(ErrBadDim)
+		}
+		for j, block := range line {
+			m, n := block.Dim()
+			if j == 0 {
+				heights[i] = m
+			} else if m != heights[i] {
+				panic(ErrBadDim)
+			}
+			if i == 0 {
+				widths[j] = n
+			} else if n != widths[j] {
+				panic(ErrBadDim)
+			}
+		}
+	}
+
+	m, n := 0, 0
+	for _, h := range heights {
+		m += h
+	}
+	for _, w := range widths {
+		n += w
+	}
+	ret := NewMatrix(m, n)
+	for i, row := 0, 0; i < len(blocks); row, i = row+heights[i], i+1 {
+		for j, col := 0, 0; j < len(widths); col, j = col+widths[j], j+1 {
+			ret.SetSubMatrix(blocks[i][j], row, col)
+		}
+	}
+	return ret
+}
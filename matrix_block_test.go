@@ -0,0 +1,95 @@
+package ml_test
+
+import (
+	"testing"
+
+	"github.com/creack/ml"
+)
+
+func TestMulNonSquare(t *testing.T) {
+	m1 := ml.Matrix{
+		{1, 2, 3},
+		{4, 5, 6},
+	}
+	m2 := ml.Matrix{
+		{1, 0},
+		{0, 1},
+		{1, 1},
+	}
+	expect := ml.Matrix{
+		{4, 5},
+		{10, 11},
+	}
+	if ret := m1.Mul(m2); !ret.Equal(expect) {
+		t.Fatalf("Unexpected product\n%s\n*\n%s\n--->\n%s\n", m1, m2, ret)
+	}
+}
+
+func TestKron(t *testing.T) {
+	m1 := ml.Matrix{
+		{1, 2},
+		{3, 4},
+	}
+	m2 := ml.Matrix{
+		{0, 5},
+		{6, 7},
+	}
+	expect := ml.Matrix{
+		{0, 5, 0, 10},
+		{6, 7, 12, 14},
+		{0, 15, 0, 20},
+		{18, 21, 24, 28},
+	}
+	if ret := m1.Kron(m2); !ret.Equal(expect) {
+		t.Fatalf("Unexpected kronecker product\nGot:\n%s\nExpect:\n%s\n", ret, expect)
+	}
+	if ret := m1.Kron(ml.Matrix{{1, 1, 1}}); !ret.Equal(ml.Matrix{{1, 1, 1, 2, 2, 2}, {3, 3, 3, 4, 4, 4}}) {
+		t.Fatalf("Unexpected kronecker product with row\n%s\n", ret)
+	}
+}
+
+func TestOuter(t *testing.T) {
+	v1 := ml.Vector{{1}, {2}, {3}}
+	v2 := ml.Vector{{4}, {5}}
+	expect := ml.Matrix{
+		{4, 5},
+		{8, 10},
+		{12, 15},
+	}
+	if ret := ml.Outer(v1, v2); !ret.Equal(expect) {
+		t.Fatalf("Unexpected outer product\nGot:\n%s\nExpect:\n%s\n", ret, expect)
+	}
+}
+
+func TestBlock(t *testing.T) {
+	a := ml.Matrix{{1, 2}, {3, 4}}
+	b := ml.Matrix{{5}, {6}}
+	c := ml.Matrix{{7, 8}}
+	d := ml.Matrix{{9}}
+	expect := ml.Matrix{
+		{1, 2, 5},
+		{3, 4, 6},
+		{7, 8, 9},
+	}
+	if ret := ml.Block([][]ml.Matrix{{a, b}, {c, d}}); !ret.Equal(expect) {
+		t.Fatalf("Unexpected block matrix\nGot:\n%s\nExpect:\n%s\n", ret, expect)
+	}
+}
+
+func TestBlockBadDim(t *testing.T) {
+	a := ml.Matrix{{1, 2}, {3, 4}}
+	for i, blocks := range [][][]ml.Matrix{
+		{{a, ml.Matrix{{5}}}},                           // Row count mismatch within a grid row.
+		{{a}, {ml.Matrix{{7, 8, 9}}}},                   // Col count mismatch within a grid col.
+		{{a, ml.Matrix{{5}, {6}}}, {ml.Matrix{{7, 8}}}}, // Ragged grid.
+	} {
+		func() {
+			defer func() {
+				if err := recover(); err != ml.ErrBadDim {
+					t.Fatalf("[%d] Unexpected panic for misaligned blocks: %v", i, err)
+				}
+			}()
+			ml.Block(blocks)
+		}()
+	}
+}
@@ -0,0 +1,160 @@
+package ml
+
+import (
+	"math"
+)
+
+// Pow returns the current matrix raised to the k'th power.
+// Uses repeated squaring, so only O(log k) multiplications are needed.
+// Pow(0) is the identity and negative powers are powers of the inverse.
+// NOTE: Does not change current matrix state.
+func (ma Matrix) Pow(k int) Matrix {
+	m, n := ma.Dim()
+	if m != n {
+		panic(ErrBadDim)
+	}
+	base := ma
+	if k < 0 {
+		base = ma.Inverse()
+		k = -k
+	}
+	ret := ma.Identity()
+	for ; k > 0; k >>= 1 {
+		if k&1 == 1 {
+			ret = ret.Mul(base)
+		}
+		if k > 1 {
+			base = base.Mul(base)
+		}
+	}
+	return ret
+}
+
+// Exp returns the matrix exponential of the current matrix.
+// Uses the scaling and squaring method with a (6,6) Padé approximant:
+// the matrix is scaled by 2^-s so its norm is below 1/2, the approximant
+// is evaluated and the result is squared s times.
+// NOTE: Does not change current matrix state.
+func (ma Matrix) Exp() Matrix {
+	m, n := ma.Dim()
+	if m != n {
+		panic(ErrBadDim)
+	}
+	const q = 6
+
+	// Scale the matrix so that ||A/2^s|| < 1/2.
+	s := 0
+	if norm := ma.normInf(); norm > 0 {
+		_, e := math.Frexp(norm)
+		if s = e + 1; s < 0 {
+			s = 0
+		}
+	}
+	a := ma.Scale(math.Ldexp(1, -s))
+
+	// Evaluate the numerator (e) and denominator (d) of the approximant.
+	c := 0.5
+	x := a
+	e := a.Identity().Add(a.Scale(c))
+	d := a.Identity().Sub(a.Scale(c))
+	for k := 2; k <= q; k++ {
+		c = c * float64(q-k+1) / float64(k*(2*q-k+1))
+		x = a.Mul(x)
+		cx := x.Scale(c)
+		e = e.Add(cx)
+		if k%2 == 0 {
+			d = d.Add(cx)
+		} else {
+			d = d.Sub(cx)
+		}
+	}
+	ret := d.Inverse().Mul(e)
+
+	// Undo the scaling.
+	for k := 0; k < s; k++ {
+		ret = ret.Mul(ret)
+	}
+	return ret
+}
+
+// Sqrt returns the principal square root of the current matrix,
+// i.e. the symmetric positive definite matrix X such as X*X = A.
+// The current matrix needs to be symmetric positive definite.
+// Uses the Denman–Beavers iteration.
+// NOTE: Does not change current matrix state.
+func (ma Matrix) Sqrt() Matrix {
+	m, n := ma.Dim()
+	if m != n {
+		panic(ErrBadDim)
+	}
+	if !ma.isSPD() {
+		panic(ErrNotSPD)
+	}
+	const (
+		maxIter = 100
+		tol     = 1e-14
+	)
+	y, z := ma.Copy(), ma.Identity()
+	for i := 0; i < maxIter; i++ {
+		yNext := y.Add(z.Inverse()).Scale(0.5)
+		zNext := z.Add(y.Inverse()).Scale(0.5)
+		delta := yNext.Sub(y).normInf()
+		y, z = yNext, zNext
+		if delta <= tol*y.normInf() {
+			// Remove the asymmetry introduced by rounding.
+			return y.Add(y.Transpose()).Scale(0.5)
+		}
+	}
+	panic(ErrNoConvergence)
+}
+
+// normInf returns the infinity norm of the current matrix (max absolute row sum).
+func (ma Matrix) normInf() float64 {
+	norm := 0.
+	for _, line := range ma {
+		sum := 0.
+		for _, elem := range line {
+			sum += math.Abs(elem)
+		}
+		if sum > norm {
+			norm = sum
+		}
+	}
+	return norm
+}
+
+// isSPD checks if the current matrix is symmetric positive definite
+// by attempting a Cholesky factorization.
+func (ma Matrix) isSPD() bool {
+	m, n := ma.Dim()
+	if m != n {
+		return false
+	}
+	tol := 1e-12 * ma.normInf()
+	for i := 0; i < m; i++ {
+		for j := 0; j < i; j++ {
+			if math.Abs(ma[i][j]-ma[j][i]) > tol {
+				return false
+			}
+		}
+	}
+	l := NewMatrix(m, n)
+	for j := 0; j < n; j++ {
+		d := ma[j][j]
+		for k := 0; k < j; k++ {
+			d -= l[j][k] * l[j][k]
+		}
+		if d <= 0 {
+			return false
+		}
+		l[j][j] = math.Sqrt(d)
+		for i := j + 1; i < n; i++ {
+			sum := ma[i][j]
+			for k := 0; k < j; k++ {
+				sum -= l[i][k] * l[j][k]
+			}
+			l[i][j] = sum / l[j][j]
+		}
+	}
+	return true
+}
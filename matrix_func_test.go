@@ -0,0 +1,105 @@
+package ml_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/creack/ml"
+)
+
+// approxEqual compares the two given matrices with an absolute tolerance.
+func approxEqual(ma1, ma2 ml.Matrix, tol float64) bool {
+	if !ma1.DimMatch(ma2) {
+		return false
+	}
+	for i, line := range ma1 {
+		for j := range line {
+			if math.Abs(ma1[i][j]-ma2[i][j]) > tol {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func TestPow(t *testing.T) {
+	m1 := ml.Matrix{
+		{1, 2},
+		{3, 4},
+	}
+	if ret := m1.Pow(0); !ret.Equal(m1.Identity()) {
+		t.Fatalf("m1^0 is not the identity\n%s\n", ret)
+	}
+	if ret := m1.Pow(1); !ret.Equal(m1) {
+		t.Fatalf("m1^1 != m1\n%s\n", ret)
+	}
+	for k := 2; k < 8; k++ {
+		expect := m1
+		for i := 1; i < k; i++ {
+			expect = expect.Mul(m1)
+		}
+		if ret := m1.Pow(k); !ret.Equal(expect) {
+			t.Fatalf("Unexpected m1^%d\nGot:\n%s\nExpect:\n%s\n", k, ret, expect)
+		}
+	}
+	if ret := m1.Pow(-2); !approxEqual(ret.Mul(m1.Pow(2)), m1.Identity(), 1e-12) {
+		t.Fatalf("m1^-2 * m1^2 is not the identity\n%s\n", ret)
+	}
+}
+
+func TestExp(t *testing.T) {
+	for i, elem := range []struct {
+		in, expect ml.Matrix
+	}{
+		{ml.Matrix{{0, 0}, {0, 0}}, ml.Matrix{{1, 0}, {0, 1}}},
+		{ml.Matrix{{1, 0}, {0, -2}}, ml.Matrix{{math.E, 0}, {0, math.Exp(-2)}}},
+		{ml.Matrix{{0, 1}, {0, 0}}, ml.Matrix{{1, 1}, {0, 1}}},
+		{ml.Matrix{{0, -3}, {3, 0}}, ml.Matrix{{math.Cos(3), -math.Sin(3)}, {math.Sin(3), math.Cos(3)}}},
+		{ml.Matrix{{10, 0}, {0, 0.5}}, ml.Matrix{{math.Exp(10), 0}, {0, math.Exp(0.5)}}},
+	} {
+		ret := elem.in.Exp()
+		for j, line := range ret {
+			for k := range line {
+				if diff := math.Abs(ret[j][k] - elem.expect[j][k]); diff > 1e-12*math.Max(1, math.Abs(elem.expect[j][k])) {
+					t.Fatalf("[%d] Unexpected matrix exponential\nGot:\n%s\nExpect:\n%s\n", i, ret, elem.expect)
+				}
+			}
+		}
+	}
+}
+
+func TestSqrt(t *testing.T) {
+	if ret := (ml.Matrix{{4, 0}, {0, 9}}).Sqrt(); !approxEqual(ret, ml.Matrix{{2, 0}, {0, 3}}, 1e-12) {
+		t.Fatalf("Unexpected square root\n%s\n", ret)
+	}
+	m1 := ml.Matrix{
+		{4, 1, 2},
+		{1, 5, 3},
+		{2, 3, 6},
+	}
+	ret := m1.Sqrt()
+	if !approxEqual(ret.Mul(ret), m1, 1e-12) {
+		t.Fatalf("sqrt(m1) * sqrt(m1) != m1\n%s\n--->\n%s\n", ret, ret.Mul(ret))
+	}
+	if !ret.Equal(ret.Transpose()) {
+		t.Fatalf("sqrt(m1) is not symmetric\n%s\n", ret)
+	}
+}
+
+func TestSqrtNotSPD(t *testing.T) {
+	for i, elem := range []ml.Matrix{
+		{{1, 2}, {3, 4}}, // Not symmetric.
+		{{1, 2}, {2, 1}}, // Negative eigen value.
+		{{0, 0}, {0, 0}}, // Singular.
+		{{1, 2, 3}},      // Not square.
+	} {
+		func() {
+			defer func() {
+				if err := recover(); err == nil {
+					t.Fatalf("[%d] no panic received when taking the square root of\n%s\n", i, elem)
+				}
+			}()
+			elem.Sqrt()
+		}()
+	}
+}
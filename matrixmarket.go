@@ -0,0 +1,324 @@
+package ml
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Matrix Market errors.
+var (
+	ErrInvalidMatrixMarket     = errors.New("invalid matrix market data")
+	ErrUnsupportedMatrixMarket = errors.New("unsupported matrix market type")
+	ErrNotSymmetric            = errors.New("the matrix is not symmetric")
+)
+
+// Coordinate files are densified: the dense matrix can be mmMaxExpansion times
+// bigger than its stored entries, plus mmMinDense elements.
+const (
+	mmMaxExpansion = 1024
+	mmMinDense     = 1 << 20
+)
+
+// MatrixMarketFormat is the storage format of a Matrix Market file.
+type MatrixMarketFormat string
+
+// Matrix Market formats.
+const (
+	MatrixMarketCoordinate MatrixMarketFormat = "coordinate" // Sparse: list of (i,j,value).
+	MatrixMarketArray      MatrixMarketFormat = "array"      // Dense: column-major values.
+)
+
+// MatrixMarketSymmetry is the symmetry of a Matrix Market file.
+type MatrixMarketSymmetry string
+
+// Matrix Market symmetries.
+const (
+	MatrixMarketGeneral       MatrixMarketSymmetry = "general"
+	MatrixMarketSymmetric     MatrixMarketSymmetry = "symmetric"      // Only the lower triangle is stored.
+	MatrixMarketSkewSymmetric MatrixMarketSymmetry = "skew-symmetric" // Only the strict lower triangle is stored.
+)
+
+// mmScanner reads the non-comment lines of a Matrix Market file.
+type mmScanner struct {
+	*bufio.Scanner
+	line int
+}
+
+// next returns the fields of the next non empty, non comment line.
+func (s *mmScanner) next() ([]string, error) {
+	for s.Scan() {
+		s.line++
+		text := strings.TrimSpace(s.Text())
+		if text == "" || text[0] == '%' {
+			continue
+		}
+		return strings.Fields(text), nil
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	return nil, fmt.Errorf("%w: unexpected end of file", ErrInvalidMatrixMarket)
+}
+
+// errorf wraps ErrInvalidMatrixMarket with the current line number.
+func (s *mmScanner) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: line %d: %s", ErrInvalidMatrixMarket, s.line, fmt.Sprintf(format, args...))
+}
+
+// ints parses the given fields as integers.
+func (s *mmScanner) ints(fields []string, n int) ([]int, error) {
+	if len(fields) != n {
+		return nil, s.errorf("expected %d fields, got %d", n, len(fields))
+	}
+	ret := make([]int, n)
+	for i, field := range fields {
+		v, err := strconv.Atoi(field)
+		if err != nil || v < 0 {
+			return nil, s.errorf("invalid integer %q", field)
+		}
+		ret[i] = v
+	}
+	return ret, nil
+}
+
+// ReadMatrixMarket reads a matrix in Matrix Market exchange format.
+// Supports the coordinate and array formats with real, integer or pattern
+// fields and general, symmetric or skew-symmetric symmetry.
+// The matrix is allocated once its values are read. Coordinate files with much
+// fewer entries than elements are rejected with ErrInvalidMatrixMarket.
+func ReadMatrixMarket(r io.Reader) (Matrix, error) {
+	s := &mmScanner{Scanner: bufio.NewScanner(r)}
+
+	// Banner: %%MatrixMarket matrix <format> <field> <symmetry>
+	if !s.Scan() {
+		if err := s.Err(); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w: missing banner", ErrInvalidMatrixMarket)
+	}
+	s.line++
+	banner := strings.Fields(strings.ToLower(s.Text()))
+	if len(banner) != 5 || banner[0] != "%%matrixmarket" || banner[1] != "matrix" {
+		return nil, s.errorf("invalid banner %q", s.Text())
+	}
+	format, field, symmetry := MatrixMarketFormat(banner[2]), banner[3], MatrixMarketSymmetry(banner[4])
+	if format != MatrixMarketCoordinate && format != MatrixMarketArray {
+		return nil, fmt.Errorf("%w: format %q", ErrUnsupportedMatrixMarket, format)
+	}
+	if field != "real" && field != "integer" && (field != "pattern" || format != MatrixMarketCoordinate) {
+		return nil, fmt.Errorf("%w: field %q", ErrUnsupportedMatrixMarket, field)
+	}
+	if symmetry != MatrixMarketGeneral && symmetry != MatrixMarketSymmetric && symmetry != MatrixMarketSkewSymmetric {
+		return nil, fmt.Errorf("%w: symmetry %q", ErrUnsupportedMatrixMarket, symmetry)
+	}
+
+	// Size line.
+	fields, err := s.next()
+	if err != nil {
+		return nil, err
+	}
+	sizeFields := 3
+	if format == MatrixMarketArray {
+		sizeFields = 2
+	}
+	size, err := s.ints(fields, sizeFields)
+	if err != nil {
+		return nil, err
+	}
+	m, n := size[0], size[1]
+	if symmetry != MatrixMarketGeneral && m != n {
+		return nil, s.errorf("%s matrix needs to be square, got (%d,%d)", symmetry, m, n)
+	}
+	if (m == 0) != (n == 0) || (n != 0 && m > math.MaxInt/n) {
+		return nil, s.errorf("invalid size (%d,%d)", m, n)
+	}
+
+	// check validates the (i,j) element.
+	check := func(i, j int, v float64) error {
+		if i < 0 || j < 0 || i >= m || j >= n {
+			return s.errorf("index (%d,%d) out of bound", i+1, j+1)
+		}
+		if symmetry != MatrixMarketGeneral && j > i {
+			return s.errorf("index (%d,%d) above the diagonal of a %s matrix", i+1, j+1, symmetry)
+		}
+		if symmetry == MatrixMarketSkewSymmetric && i == j && v != 0 {
+			return s.errorf("non zero diagonal in skew-symmetric matrix")
+		}
+		return nil
+	}
+	// set sets the (i,j) element and its mirror if needed.
+	set := func(ret Matrix, i, j int, v float64) {
+		ret[i][j] = v
+		switch symmetry {
+		case MatrixMarketSymmetric:
+			ret[j][i] = v
+		case MatrixMarketSkewSymmetric:
+			ret[j][i] = -v
+		}
+	}
+	parseFloat := func(field string) (float64, error) {
+		v, err := strconv.ParseFloat(field, 64)
+		if err != nil {
+			return 0, s.errorf("invalid value %q", field)
+		}
+		return v, nil
+	}
+
+	if format == MatrixMarketCoordinate {
+		nnz := size[2]
+		if nnz > m*n {
+			return nil, s.errorf("%d entries for a (%d,%d) matrix", nnz, m, n)
+		}
+		valueFields := 3
+		if field == "pattern" {
+			valueFields = 2
+		}
+		type entry struct {
+			i, j int
+			v    float64
+		}
+		var entries []entry
+		for k := 0; k < nnz; k++ {
+			if fields, err = s.next(); err != nil {
+				return nil, err
+			}
+			if len(fields) != valueFields {
+				return nil, s.errorf("expected %d fields, got %d", valueFields, len(fields))
+			}
+			idx, err := s.ints(fields[:2], 2)
+			if err != nil {
+				return nil, err
+			}
+			v := 1.
+			if field != "pattern" {
+				if v, err = parseFloat(fields[2]); err != nil {
+					return nil, err
+				}
+			}
+			if err := check(idx[0]-1, idx[1]-1, v); err != nil {
+				return nil, err
+			}
+			entries = append(entries, entry{i: idx[0] - 1, j: idx[1] - 1, v: v})
+		}
+		if m*n > mmMinDense && (m*n-mmMinDense)/mmMaxExpansion > nnz {
+			return nil, s.errorf("(%d,%d) matrix too large for %d entries", m, n, nnz)
+		}
+		ret := NewMatrix(m, n)
+		for _, e := range entries {
+			set(ret, e.i, e.j, e.v)
+		}
+		return ret, nil
+	}
+
+	// Array: column-major, only the lower triangle for symmetric matrices.
+	// lower returns the first stored row of the given column.
+	lower := func(j int) int {
+		switch symmetry {
+		case MatrixMarketSymmetric:
+			return j
+		case MatrixMarketSkewSymmetric:
+			return j + 1
+		}
+		return 0
+	}
+	var values []float64
+	for j := 0; j < n; j++ {
+		for i := lower(j); i < m; i++ {
+			if fields, err = s.next(); err != nil {
+				return nil, err
+			}
+			if len(fields) != 1 {
+				return nil, s.errorf("expected 1 field, got %d", len(fields))
+			}
+			v, err := parseFloat(fields[0])
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, v)
+		}
+	}
+	ret := NewMatrix(m, n)
+	k := 0
+	for j := 0; j < n; j++ {
+		for i := lower(j); i < m; i++ {
+			set(ret, i, j, values[k])
+			k++
+		}
+	}
+	return ret, nil
+}
+
+// WriteMatrixMarket writes the given matrix in Matrix Market exchange format
+// with a real field.
+// The coordinate format only stores the non zero elements.
+// The symmetric symmetries only store the lower triangle and fail with
+// ErrNotSymmetric if the matrix does not have the requested symmetry.
+func WriteMatrixMarket(w io.Writer, ma Matrix, format MatrixMarketFormat, symmetry MatrixMarketSymmetry) error {
+	if err := ma.Validate(); err != nil {
+		return err
+	}
+	if format != MatrixMarketCoordinate && format != MatrixMarketArray {
+		return fmt.Errorf("%w: format %q", ErrUnsupportedMatrixMarket, format)
+	}
+	m, n := ma.shape()
+	// lower returns the first stored row of the given column.
+	var lower func(j int) int
+	switch symmetry {
+	case MatrixMarketGeneral:
+		lower = func(int) int { return 0 }
+	case MatrixMarketSymmetric, MatrixMarketSkewSymmetric:
+		if m != n {
+			return ErrBadDim
+		}
+		sign := 1.
+		lower = func(j int) int { return j }
+		if symmetry == MatrixMarketSkewSymmetric {
+			sign = -1
+			lower = func(j int) int { return j + 1 }
+		}
+		for i := 0; i < m; i++ {
+			for j := 0; j <= i; j++ {
+				if ma[i][j] != sign*ma[j][i] {
+					return ErrNotSymmetric
+				}
+			}
+		}
+	default:
+		return fmt.Errorf("%w: symmetry %q", ErrUnsupportedMatrixMarket, symmetry)
+	}
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "%%%%MatrixMarket matrix %s real %s\n", format, symmetry)
+	if format == MatrixMarketArray {
+		fmt.Fprintf(bw, "%d %d\n", m, n)
+		for j := 0; j < n; j++ {
+			for i := lower(j); i < m; i++ {
+				fmt.Fprintf(bw, "%s\n", strconv.FormatFloat(ma[i][j], 'g', -1, 64))
+			}
+		}
+		return bw.Flush()
+	}
+
+	nz := 0
+	for j := 0; j < n; j++ {
+		for i := lower(j); i < m; i++ {
+			if ma[i][j] != 0 {
+				nz++
+			}
+		}
+	}
+	fmt.Fprintf(bw, "%d %d %d\n", m, n, nz)
+	for j := 0; j < n; j++ {
+		for i := lower(j); i < m; i++ {
+			if ma[i][j] != 0 {
+				fmt.Fprintf(bw, "%d %d %s\n", i+1, j+1, strconv.FormatFloat(ma[i][j], 'g', -1, 64))
+			}
+		}
+	}
+	return bw.Flush()
+}
@@ -0,0 +1,144 @@
+package ml_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/creack/ml"
+)
+
+func TestReadMatrixMarket(t *testing.T) {
+	for i, elem := range []struct {
+		in     string
+		expect ml.Matrix
+	}{
+		{
+			in: `%%MatrixMarket matrix coordinate real general
+% A comment.
+2 3 3
+1 1 1.5
+2 3 -2
+1 2 4e1
+`,
+			expect: ml.Matrix{{1.5, 40, 0}, {0, 0, -2}},
+		},
+		{
+			in: `%%MatrixMarket matrix array integer general
+2 2
+1
+2
+3
+4
+`,
+			expect: ml.Matrix{{1, 3}, {2, 4}},
+		},
+		{
+			in: `%%MatrixMarket matrix array real symmetric
+3 3
+1
+2
+3
+4
+5
+6
+`,
+			expect: ml.Matrix{{1, 2, 3}, {2, 4, 5}, {3, 5, 6}},
+		},
+		{
+			in: `%%MatrixMarket matrix coordinate pattern symmetric
+2 2 2
+1 1
+2 1
+`,
+			expect: ml.Matrix{{1, 1}, {1, 0}},
+		},
+		{
+			in: `%%MatrixMarket matrix coordinate real skew-symmetric
+3 3 1
+3 1 2
+`,
+			expect: ml.Matrix{{0, 0, -2}, {0, 0, 0}, {2, 0, 0}},
+		},
+	} {
+		ret, err := ml.ReadMatrixMarket(strings.NewReader(elem.in))
+		if err != nil {
+			t.Fatalf("[%d] Error reading matrix market: %s", i, err)
+		}
+		if !ret.Equal(elem.expect) {
+			t.Fatalf("[%d] Unexpected matrix\nGot:\n%s\nExpect:\n%s\n", i, ret, elem.expect)
+		}
+	}
+}
+
+func TestReadMatrixMarketInvalid(t *testing.T) {
+	for i, elem := range []struct {
+		in     string
+		expect error
+	}{
+		{"", ml.ErrInvalidMatrixMarket},
+		{"%%MatrixMarket matrix coordinate complex general\n1 1 0\n", ml.ErrUnsupportedMatrixMarket},
+		{"%%MatrixMarket matrix coordinate real hermitian\n1 1 0\n", ml.ErrUnsupportedMatrixMarket},
+		{"%%MatrixMarket matrix coordinate real general\n2 2 2\n1 1 1\n", ml.ErrInvalidMatrixMarket},
+		{"%%MatrixMarket matrix coordinate real general\n2 2 1\n3 1 1\n", ml.ErrInvalidMatrixMarket},
+		{"%%MatrixMarket matrix coordinate real symmetric\n2 2 1\n1 2 1\n", ml.ErrInvalidMatrixMarket},
+		{"%%MatrixMarket matrix array real general\n1 1\nabc\n", ml.ErrInvalidMatrixMarket},
+		{"%%MatrixMarket matrix coordinate real general\n1000000000 1000000000 0\n", ml.ErrInvalidMatrixMarket},
+		{"%%MatrixMarket matrix coordinate real general\n16384 8192 0\n", ml.ErrInvalidMatrixMarket},
+		{"%%MatrixMarket matrix coordinate real general\n2 2 5\n", ml.ErrInvalidMatrixMarket},
+		{"%%MatrixMarket matrix array real general\n1000000000 1000000000\n1\n", ml.ErrInvalidMatrixMarket},
+		{"%%MatrixMarket matrix coordinate real general\n9223372036854775807 2 0\n", ml.ErrInvalidMatrixMarket},
+		{"%%MatrixMarket matrix array real general\n3 0\n", ml.ErrInvalidMatrixMarket},
+	} {
+		if _, err := ml.ReadMatrixMarket(strings.NewReader(elem.in)); !errors.Is(err, elem.expect) {
+			t.Errorf("[%d] Unexpected error.\nExpect:\t%v\nGot:\t%v", i, elem.expect, err)
+		}
+	}
+}
+
+func TestMatrixMarketRoundTrip(t *testing.T) {
+	sym := ml.Matrix{
+		{4, 1, 0},
+		{1, 5, 3},
+		{0, 3, 6.25},
+	}
+	skew := ml.Matrix{
+		{0, -1, 2},
+		{1, 0, 0},
+		{-2, 0, 0},
+	}
+	general := ml.Matrix{
+		{1, 0, 3},
+		{0, -5, 0},
+	}
+	for i, elem := range []struct {
+		m        ml.Matrix
+		symmetry ml.MatrixMarketSymmetry
+	}{
+		{general, ml.MatrixMarketGeneral},
+		{sym, ml.MatrixMarketGeneral},
+		{sym, ml.MatrixMarketSymmetric},
+		{skew, ml.MatrixMarketSkewSymmetric},
+	} {
+		for _, format := range []ml.MatrixMarketFormat{ml.MatrixMarketCoordinate, ml.MatrixMarketArray} {
+			buf := bytes.NewBuffer(nil)
+			if err := ml.WriteMatrixMarket(buf, elem.m, format, elem.symmetry); err != nil {
+				t.Fatalf("[%d] Error writing %s %s matrix market: %s", i, format, elem.symmetry, err)
+			}
+			ret, err := ml.ReadMatrixMarket(buf)
+			if err != nil {
+				t.Fatalf("[%d] Error reading %s %s matrix market: %s", i, format, elem.symmetry, err)
+			}
+			if !ret.Equal(elem.m) {
+				t.Fatalf("[%d] Unexpected %s %s matrix\nGot:\n%s\nExpect:\n%s\n", i, format, elem.symmetry, ret, elem.m)
+			}
+		}
+	}
+	if err := ml.WriteMatrixMarket(bytes.NewBuffer(nil), skew, ml.MatrixMarketArray, ml.MatrixMarketSymmetric); err != ml.ErrNotSymmetric {
+		t.Fatalf("Unexpected error writing non symmetric matrix as symmetric: %v", err)
+	}
+	if err := ml.WriteMatrixMarket(bytes.NewBuffer(nil), general, ml.MatrixMarketArray, ml.MatrixMarketSymmetric); err != ml.ErrBadDim {
+		t.Fatalf("Unexpected error writing non square matrix as symmetric: %v", err)
+	}
+}
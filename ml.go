@@ -1,8 +1,8 @@
 package ml
 
 import (
+	"context"
 	"fmt"
-	"log"
 )
 
 // Hypothesis .
@@ -12,9 +12,32 @@ type Hypothesis interface {
 }
 
 // Dataset .
+// Y holds one target per example, a validated (m,1) vector: the value to regress
+// or the class label. Decoding a multi-column Y fails with ErrNotAVector.
 type Dataset struct {
 	X Matrix `json:"x"`
 	Y Vector `json:"y"`
+
+	// Targets holds multiple targets per example, a (m,k) matrix, used instead of Y
+	// when set. This is the one-hot path: SoftmaxRegression and softmax MLP accept
+	// either Y class labels or Targets one-hot rows, see Dataset.OneHot.
+	// Other MLP outputs read one target per output from it.
+	Targets Matrix `json:"targets,omitempty"`
+}
+
+// withBias returns the dataset with the x(0) = 1 column added to X,
+// unless X already has n columns.
+// NOTE: Does not change the current dataset state.
+func (ds Dataset) withBias(n int) Dataset {
+	m, n1 := ds.X.Dim()
+	if n1 == n {
+		return ds
+	}
+	ds.X = NewMatrix(m, n1+1).SetSubMatrix(ds.X, 0, 1)
+	for i := 0; i < len(ds.X); i++ {
+		ds.X[i][0] = 1
+	}
+	return ds
 }
 
 // // PlotData returns the gnuplot generated ascii graph of the current dataset.
@@ -71,13 +94,24 @@ type Dataset struct {
 
 // LinearRegression is the linar regression algorithm.
 //   - Hypothesis: h(x) = Θ[0][0] + Θ[1][0]*x
+//
+// Lambda and L1Ratio configure the regularization, see penalty.
 type LinearRegression struct {
 	Θ Vector
+
+	Lambda  float64 // Regularization strength, 0 to disable.
+	L1Ratio float64 // Elastic net mix: 0 for ridge (L2), 1 for lasso (L1).
 }
 
 // Fct implements the hypothesis function.
 func (b LinearRegression) Fct(x Vector) float64 {
-	return b.Θ.Transpose().MulV(x).Sum()
+	return b.Θ.T().Mul(Matrix(x).View())[0][0]
+}
+
+// predict applies the hypothesis function to the given dataset row.
+// Avoids copying the row to a vector.
+func (b LinearRegression) predict(row MRow) float64 {
+	return b.Θ.T().Mul(Matrix{row}.T())[0][0]
 }
 
 // // Plot returns a gnuplot formatted data list.
@@ -102,93 +136,77 @@ func (b LinearRegression) Fct(x Vector) float64 {
 // The squared error equation is:
 // $$\frac{1}{2m}\sum_{i=1}^{m} (h(x^{(i)})-y^{(i)})^2 $$
 // - 1/(2m) * Sum from i=1 to m of square h(x(i))-y(i).
+// When Lambda is set, the regularization penalty / m is added.
 // TODO: rename CostFunction() ?
 func (b LinearRegression) SquaredError(dataset Dataset) float64 {
 
 	// Add x(0) = 1 column to dataset.
-	m, n := dataset.X.Dim()
-	if n != len(b.Θ) {
-		dataset.X = NewMatrix(m, n+1).SetSubMatrix(dataset.X, 0, 1)
-		for i := 0; i < len(dataset.X); i++ {
-			dataset.X[i][0] = 1
-		}
-	}
+	dataset = dataset.withBias(len(b.Θ))
+	m, _ := dataset.X.Dim()
 	// Process the sum of square error.
-	var sum float64
+	var sum Accumulator
 	for i := 0; i < m; i++ {
-		ret := b.Fct(dataset.X[i].ToVector())
+		ret := b.predict(dataset.X[i])
 		tmp := ret - dataset.Y[i][0]
-		sum += tmp * tmp
+		sum.Add(tmp * tmp)
 	}
-	// 1/2m * sum.
-	return (1 / (2 * float64(m))) * sum
+	// 1/2m * sum + regularization.
+	return checkValue("SquaredError", (1/(2*float64(m)))*sum.Sum()+b.penalty()/float64(m))
 }
 
-// PartialDerivative .
+// PartialDerivative returns the partial derivative of the squared error for Θ[j],
+// including the regularization term.
 func (b LinearRegression) PartialDerivative(dataset Dataset, j int) float64 {
 
 	// Add x(0) = 1 column to dataset.
-	m, n := dataset.X.Dim()
-	if n != len(b.Θ) {
-		dataset.X = NewMatrix(m, n+1).SetSubMatrix(dataset.X, 0, 1)
-		for i := 0; i < len(dataset.X); i++ {
-			dataset.X[i][0] = 1
-		}
-	}
+	dataset = dataset.withBias(len(b.Θ))
+	m, _ := dataset.X.Dim()
 
-	var sum float64
+	var sum Accumulator
 
 	for i := 0; i < m; i++ {
-		ret := b.Fct(dataset.X[i].ToVector())
+		ret := b.predict(dataset.X[i])
 		tmp := ret - dataset.Y[i][0]
-		sum += tmp * dataset.X[i][j]
+		sum.Add(tmp * dataset.X[i][j])
 	}
-	// 1/2 * sum.
-	return (1 / float64(m)) * sum
+	// 1/m * sum + regularization.
+	return checkValue("PartialDerivative", (1/float64(m))*(sum.Sum()+b.penaltyDerivative(j)))
 }
 
-// GradientDescent .
-func (b *LinearRegression) GradientDescent(dataset Dataset, alpha float64, plotData bool) <-chan string {
-	ch := make(chan string)
-	go func() {
-		defer close(ch)
-
-		m, n := dataset.X.Dim()
-		if n != len(b.Θ) {
-			dataset.X = NewMatrix(m, n+1).SetSubMatrix(dataset.X, 0, 1)
-			for i := 0; i < len(dataset.X); i++ {
-				dataset.X[i][0] = 1
-			}
-		}
+// Parameters implements Model.
+func (b LinearRegression) Parameters() Vector {
+	return b.Θ
+}
+
+// Cost implements Model, the squared error.
+func (b LinearRegression) Cost(dataset Dataset) float64 {
+	return b.SquaredError(dataset)
+}
 
-		for i := 0; i < 1e9; i++ {
-			if int(b.SquaredError(dataset)*1e20) == 0 {
-				println("----> converged in ", i, "steps")
-				return
-			}
-			tmp := make([]float64, len(b.Θ))
-			for j := 0; j < len(b.Θ); j++ {
-				tmp[j] = b.Θ[j][0] - alpha*b.PartialDerivative(dataset, j)
-			}
-			for j, elem := range tmp {
-				b.Θ[j][0] = elem
-			}
-			// if plotData && i%100 == 0 {
-			// 	println(b.String())
-			// 	p, err := b.Plot(dataset)
-			// 	if err != nil {
-			// 		log.Printf("fail plot: %s\n", err)
-			// 		return
-			// 	}
-			// 	ch <- p
-			// }
+// Gradient implements Model, the partial derivative for each Θ component.
+// Processed in a single pass over the dataset.
+func (b LinearRegression) Gradient(dataset Dataset) Vector {
+	dataset = dataset.withBias(len(b.Θ))
+	m, _ := dataset.X.Dim()
+	sums := make([]Accumulator, len(b.Θ))
+	for i := 0; i < m; i++ {
+		tmp := b.predict(dataset.X[i]) - dataset.Y[i][0]
+		for j, x := range dataset.X[i] {
+			sums[j].Add(tmp * x)
 		}
-		log.Printf("didn't converge in 10^9 iterations")
-	}()
-	if !plotData {
-		<-ch
 	}
-	return ch
+	ret := NewVector(len(b.Θ))
+	for j := range ret {
+		ret[j][0] = checkValue("Gradient", (1/float64(m))*(sums[j].Sum()+b.penaltyDerivative(j)))
+	}
+	return ret
+}
+
+// GradientDescent trains the model with batch gradient descent until convergence
+// or until the context is done. opts configures the convergence criteria and the
+// observers, GradientDescentOptions being the defaults. See Train.
+func (b *LinearRegression) GradientDescent(ctx context.Context, dataset Dataset, alpha float64, opts TrainOptions) (TrainResult, error) {
+	return Train(ctx, b, dataset.withBias(len(b.Θ)), &SGD{Alpha: alpha}, opts)
 }
 
 func (b LinearRegression) String() string {
@@ -1,6 +1,7 @@
 package ml_test
 
 import (
+	"context"
 	"fmt"
 	"math"
 	"testing"
@@ -90,11 +91,27 @@ func TestGradientDescent(t *testing.T) {
 	}
 
 	lr := &ml.LinearRegression{Θ: parameters}
-	lr.GradientDescent(testSimpleDataset, 0.1, false)
+	res, err := lr.GradientDescent(context.Background(), testSimpleDataset, 0.1, ml.GradientDescentOptions())
+	if err != nil {
+		t.Fatalf("Error training the model: %s", err)
+	}
+	if res.Reason != ml.TerminationCostTolerance || res.Iterations == 0 || len(res.History) != res.Iterations+1 {
+		t.Fatalf("Unexpected training result: %d iterations, %s", res.Iterations, res.Reason)
+	}
 	if expect, got := stringify(0.), stringify(math.Abs(lr.Θ[0][0])); expect != got {
 		t.Fatalf("Unexpected Θ0 for gradient descent.\nExpect:\t%s\nGot:\t%s", expect, got)
 	}
 	if expect, got := stringify(1.), stringify(lr.Θ[1][0]); expect != got {
 		t.Fatalf("Unexpected Θ0 for gradient descent.\nExpect:\t%s\nGot:\t%s", expect, got)
 	}
+
+	// Custom convergence criteria.
+	lr = &ml.LinearRegression{Θ: ml.Vector{{-0.1}, {3}}}
+	res, err = lr.GradientDescent(context.Background(), testSimpleDataset, 0.1, ml.TrainOptions{MaxIterations: 5, CostTolerance: 1e-20})
+	if err != nil {
+		t.Fatalf("Error training the model: %s", err)
+	}
+	if res.Reason != ml.TerminationMaxIterations || res.Iterations != 5 {
+		t.Fatalf("Unexpected training result: %d iterations, %s", res.Iterations, res.Reason)
+	}
 }
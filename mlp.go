@@ -0,0 +1,317 @@
+package ml
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Activation is the activation function of a neural network layer.
+type Activation int
+
+// Activations.
+const (
+	ActivationIdentity Activation = iota
+	ActivationSigmoid
+	ActivationTanh
+	ActivationReLU
+	ActivationSoftmax // Output layer only, trained with the cross-entropy cost.
+)
+
+func (a Activation) String() string {
+	switch a {
+	case ActivationIdentity:
+		return "identity"
+	case ActivationSigmoid:
+		return "sigmoid"
+	case ActivationTanh:
+		return "tanh"
+	case ActivationReLU:
+		return "relu"
+	case ActivationSoftmax:
+		return "softmax"
+	}
+	return fmt.Sprintf("Activation(%d)", int(a))
+}
+
+// apply applies the activation in place on each row of z.
+func (a Activation) apply(z Matrix) Matrix {
+	for _, line := range z {
+		if a == ActivationSoftmax {
+			copy(line, Softmax(line))
+			continue
+		}
+		for j, elem := range line {
+			switch a {
+			case ActivationSigmoid:
+				line[j] = Sigmoid(elem)
+			case ActivationTanh:
+				line[j] = math.Tanh(elem)
+			case ActivationReLU:
+				line[j] = math.Max(elem, 0)
+			}
+		}
+	}
+	return z
+}
+
+// derivative returns the derivative of the activation expressed with its output.
+// Softmax is only supported combined with the cross-entropy cost.
+func (a Activation) derivative(out float64) float64 {
+	switch a {
+	case ActivationSigmoid:
+		return out * (1 - out)
+	case ActivationTanh:
+		return 1 - out*out
+	case ActivationReLU:
+		if out > 0 {
+			return 1
+		}
+		return 0
+	}
+	return 1
+}
+
+// Layer is a fully connected neural network layer.
+// W is a (in+1,out) matrix, the first row holds the bias weights, the same
+// way Θ[0] does for the regressions.
+type Layer struct {
+	W          Matrix
+	Activation Activation
+}
+
+// MLP is a feed-forward neural network (multilayer perceptron).
+// The cost depends on the output activation:
+//   - softmax: categorical cross-entropy, Y holds class labels or Targets one-hot rows.
+//   - sigmoid with 1 output: binary cross-entropy, Y holds 0/1 labels.
+//   - otherwise: squared error, Y holds the target of a single output, Targets
+//     one target per output.
+type MLP struct {
+	Layers []Layer
+}
+
+// NewMLP instantiates a network with the given layer sizes, from input to output,
+// and one activation per layer after the input.
+// Weights are randomly initialized with the given generator: He initialization
+// for ReLU layers, Glorot (Xavier) for the others. Biases start at 0.
+// The same generator can then be passed to Train to reproduce the whole training.
+// panic with ErrBadDim if the activation count does not match or if softmax
+// is not the output layer activation.
+func NewMLP(sizes []int, activations []Activation, rnd *RNG) *MLP {
+	if len(sizes) < 2 || len(activations) != len(sizes)-1 {
+		panic(ErrBadDim)
+	}
+	for _, a := range activations[:len(activations)-1] {
+		if a == ActivationSoftmax {
+			panic(ErrBadDim)
+		}
+	}
+	ret := &MLP{Layers: make([]Layer, len(activations))}
+	for l, a := range activations {
+		in, out := sizes[l], sizes[l+1]
+		limit := math.Sqrt(6 / float64(in+out))
+		if a == ActivationReLU {
+			limit = math.Sqrt(6 / float64(in))
+		}
+		w := NewMatrix(in+1, out)
+		for _, line := range w[1:] {
+			for j := range line {
+				line[j] = (2*rnd.Float64() - 1) * limit
+			}
+		}
+		ret.Layers[l] = Layer{W: w, Activation: a}
+	}
+	return ret
+}
+
+// output returns the output layer.
+func (nn MLP) output() Layer {
+	return nn.Layers[len(nn.Layers)-1]
+}
+
+// forward runs the given (m,in) batch through the network.
+// Returns the output of each layer with the bias column, the input first.
+// The last element is the network output, without bias column.
+func (nn MLP) forward(x Matrix) []Matrix {
+	outs := make([]Matrix, 0, len(nn.Layers)+1)
+	a := x
+	for _, layer := range nn.Layers {
+		a = Dataset{X: a}.withBias(len(layer.W)).X
+		outs = append(outs, a)
+		a = layer.Activation.apply(a.Mul(layer.W))
+	}
+	return append(outs, a)
+}
+
+// Forward returns the network output for the given (m,in) batch, one row per sample.
+func (nn MLP) Forward(x Matrix) Matrix {
+	outs := nn.forward(x)
+	return outs[len(outs)-1]
+}
+
+// Fct implements the hypothesis function.
+// Returns the output for single output networks and the most probable
+// class otherwise. The x(0) = 1 bias is optional.
+func (nn MLP) Fct(x Vector) float64 {
+	row := Matrix(x).Transpose()
+	if _, n := row.Dim(); n == len(nn.Layers[0].W) {
+		row = row.SubMatrix(0, 1, 1, n-1)
+	}
+	out := nn.Forward(row)[0]
+	if len(out) == 1 {
+		return out[0]
+	}
+	ret := 0
+	for c, elem := range out {
+		if elem > out[ret] {
+			ret = c
+		}
+	}
+	return float64(ret)
+}
+
+// crossEntropy checks if the network is trained with a cross-entropy cost.
+func (nn MLP) crossEntropy() bool {
+	out := nn.output()
+	_, k := out.W.Dim()
+	return out.Activation == ActivationSoftmax || (out.Activation == ActivationSigmoid && k == 1)
+}
+
+// targets returns the dataset targets as a (m,out) matrix.
+func (nn MLP) targets(dataset Dataset) Matrix {
+	out := nn.output()
+	_, k := out.W.Dim()
+	if out.Activation == ActivationSoftmax {
+		return dataset.OneHot(k)
+	}
+	y := Matrix(dataset.Y)
+	if len(dataset.Targets) > 0 {
+		y = dataset.Targets
+	}
+	if _, n := y.Dim(); n != k {
+		panic(ErrBadDim)
+	}
+	return y
+}
+
+// SquaredError process the squared error of the network on the given dataset.
+// $$\frac{1}{2m}\sum_{i=1}^{m} ||h(x^{(i)})-y^{(i)}||^2 $$
+func (nn MLP) SquaredError(dataset Dataset) float64 {
+	y := nn.targets(dataset)
+	m, _ := dataset.X.Dim()
+	var sum Accumulator
+	for i, line := range nn.Forward(dataset.X) {
+		for j, elem := range line {
+			tmp := elem - y[i][j]
+			sum.Add(tmp * tmp)
+		}
+	}
+	return checkValue("SquaredError", (1/(2*float64(m)))*sum.Sum())
+}
+
+// Cost process the cost of the network on the given dataset.
+// See MLP for the cost used depending on the output activation.
+func (nn MLP) Cost(dataset Dataset) float64 {
+	if !nn.crossEntropy() {
+		return nn.SquaredError(dataset)
+	}
+
+	// Cross-entropy, computed from the output layer scores to stay finite.
+	out := nn.output()
+	y := nn.targets(dataset)
+	outs := nn.forward(dataset.X)
+	z := outs[len(outs)-2].Mul(out.W)
+	m, _ := dataset.X.Dim()
+	var sum Accumulator
+	for i, line := range z {
+		if out.Activation == ActivationSigmoid {
+			sum.Add(softplus(line[0]) - y[i][0]*line[0])
+			continue
+		}
+		lse := logSumExp(line)
+		for c, elem := range line {
+			if y[i][c] != 0 {
+				sum.Add(y[i][c] * (lse - elem))
+			}
+		}
+	}
+	return checkValue("Cost", (1/float64(m))*sum.Sum())
+}
+
+// Gradients returns the gradient of the cost for each layer weights, using backpropagation.
+func (nn MLP) Gradients(dataset Dataset) []Matrix {
+	y := nn.targets(dataset)
+	m, _ := dataset.X.Dim()
+	outs := nn.forward(dataset.X)
+
+	// Output error. For the cross-entropy costs and the identity, it simplifies to h(x)-y.
+	out := outs[len(outs)-1]
+	last := nn.output().Activation
+	delta := out.Sub(y).Scale(1 / float64(m))
+	if !nn.crossEntropy() && last != ActivationIdentity {
+		for i, line := range delta {
+			for j := range line {
+				line[j] *= last.derivative(out[i][j])
+			}
+		}
+	}
+
+	grads := make([]Matrix, len(nn.Layers))
+	for l := len(nn.Layers) - 1; l >= 0; l-- {
+		in := outs[l]
+		grads[l] = in.T().Mul(delta.View()).check("Gradients")
+		if l == 0 {
+			break
+		}
+		// Propagate the error, skipping the bias row.
+		w := nn.Layers[l].W
+		prev := delta.Mul(w.SubMatrix(1, 0, len(w)-1, len(w[0])).Transpose())
+		a := nn.Layers[l-1].Activation
+		for i, line := range prev {
+			for j := range line {
+				line[j] *= a.derivative(in[i][j+1])
+			}
+		}
+		delta = prev
+	}
+	return grads
+}
+
+// Parameters implements Model, all the weights of the network layer by layer, row-major.
+// NOTE: Not a copy, changes to the vector affect the network.
+func (nn MLP) Parameters() Vector {
+	var ret Vector
+	for _, layer := range nn.Layers {
+		ret = append(ret, flatten(layer.W)...)
+	}
+	return ret
+}
+
+// Gradient implements Model, the gradient of the cost in the Parameters order.
+func (nn MLP) Gradient(dataset Dataset) Vector {
+	var ret Vector
+	for _, grad := range nn.Gradients(dataset) {
+		ret = append(ret, flatten(grad)...)
+	}
+	return ret
+}
+
+// Train trains the network with mini-batch gradient descent.
+// Shortcut for Train with the SGD optimizer.
+func (nn *MLP) Train(ctx context.Context, dataset Dataset, alpha float64, epochs, batchSize int, rnd *RNG) error {
+	_, err := Train(ctx, nn, dataset, &SGD{Alpha: alpha}, TrainOptions{MaxIterations: epochs, BatchSize: batchSize, Rand: rnd})
+	return err
+}
+
+func (nn MLP) String() string {
+	sizes := make([]string, 0, len(nn.Layers)+1)
+	for l, layer := range nn.Layers {
+		in, out := layer.W.Dim()
+		if l == 0 {
+			sizes = append(sizes, fmt.Sprint(in-1))
+		}
+		sizes = append(sizes, fmt.Sprintf("%d(%s)", out, layer.Activation))
+	}
+	return "MLP: " + strings.Join(sizes, " -> ")
+}
@@ -0,0 +1,115 @@
+package ml_test
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/creack/ml"
+)
+
+var testXORDataset = ml.Dataset{
+	X: ml.Matrix{{0, 0}, {0, 1}, {1, 0}, {1, 1}},
+	Y: ml.Vector{{0}, {1}, {1}, {0}},
+}
+
+// numericGradient returns the central finite difference gradient of the network cost.
+func numericGradient(nn *ml.MLP, dataset ml.Dataset) []float64 {
+	var ret []float64
+	for _, layer := range nn.Layers {
+		for _, line := range layer.W {
+			for j := range line {
+				old := line[j]
+				line[j] = old + 1e-6
+				c1 := nn.Cost(dataset)
+				line[j] = old - 1e-6
+				c2 := nn.Cost(dataset)
+				line[j] = old
+				ret = append(ret, (c1-c2)/2e-6)
+			}
+		}
+	}
+	return ret
+}
+
+func TestMLPGradient(t *testing.T) {
+	rnd := ml.NewRNG(42)
+	for i, elem := range []struct {
+		sizes       []int
+		activations []ml.Activation
+		dataset     ml.Dataset
+	}{
+		{[]int{2, 3, 1}, []ml.Activation{ml.ActivationTanh, ml.ActivationSigmoid}, testXORDataset},
+		{[]int{1, 4, 3}, []ml.Activation{ml.ActivationReLU, ml.ActivationSoftmax}, testSoftmaxDataset},
+		{[]int{2, 3, 2, 1}, []ml.Activation{ml.ActivationSigmoid, ml.ActivationTanh, ml.ActivationIdentity}, testXORDataset},
+		{[]int{2, 2}, []ml.Activation{ml.ActivationTanh}, ml.Dataset{X: testXORDataset.X, Targets: ml.Matrix{{0, 1}, {1, 0}, {1, 0}, {0, 1}}}},
+	} {
+		nn := ml.NewMLP(elem.sizes, elem.activations, rnd)
+		analytic := nn.Gradient(elem.dataset)
+		numeric := numericGradient(nn, elem.dataset)
+		if len(analytic) != len(numeric) {
+			t.Fatalf("[%d] Unexpected gradient size: %d != %d", i, len(analytic), len(numeric))
+		}
+		for j := range numeric {
+			if math.Abs(analytic[j][0]-numeric[j]) > 1e-6 {
+				t.Fatalf("[%d] Gradient mismatch for weight %d (%s).\nAnalytic:\t%g\nNumeric:\t%g", i, j, nn, analytic[j][0], numeric[j])
+			}
+		}
+	}
+}
+
+func TestMLPXOR(t *testing.T) {
+	rnd := ml.NewRNG(1)
+	nn := ml.NewMLP([]int{2, 4, 1}, []ml.Activation{ml.ActivationTanh, ml.ActivationSigmoid}, rnd)
+	if err := nn.Train(context.Background(), testXORDataset, 0.5, 5000, 2, rnd); err != nil {
+		t.Fatalf("Error training the network: %s", err)
+	}
+	var h ml.Hypothesis = nn
+	for i, x := range testXORDataset.X {
+		if expect, got := testXORDataset.Y[i][0], math.Round(h.Fct(x.ToVector())); expect != got {
+			t.Fatalf("Unexpected prediction for %v.\nExpect:\t%g\nGot:\t%g (%s)", x, expect, got, nn)
+		}
+	}
+	if cost := nn.Cost(testXORDataset); cost > 0.05 {
+		t.Fatalf("Network did not fit XOR, cost: %g", cost)
+	}
+}
+
+func TestMLPSoftmax(t *testing.T) {
+	rnd := ml.NewRNG(1)
+	nn := ml.NewMLP([]int{1, 8, 3}, []ml.Activation{ml.ActivationReLU, ml.ActivationSoftmax}, rnd)
+	if err := nn.Train(context.Background(), testSoftmaxDataset, 0.05, 2000, 3, rnd); err != nil {
+		t.Fatalf("Error training the network: %s", err)
+	}
+	for i, elem := range []struct {
+		x      float64
+		expect float64
+	}{
+		{1, 0},
+		{9, 2},
+	} {
+		if got := nn.Fct(ml.Vector{{elem.x}}); got != elem.expect {
+			t.Errorf("[%d] Unexpected class for %g: %g", i, elem.x, got)
+		}
+	}
+}
+
+func TestNewMLPInvalid(t *testing.T) {
+	for i, elem := range []struct {
+		sizes       []int
+		activations []ml.Activation
+	}{
+		{[]int{2}, nil},
+		{[]int{2, 3, 1}, []ml.Activation{ml.ActivationTanh}},
+		{[]int{2, 3, 2}, []ml.Activation{ml.ActivationSoftmax, ml.ActivationSoftmax}},
+	} {
+		func() {
+			defer func() {
+				if err := recover(); err != ml.ErrBadDim {
+					t.Fatalf("[%d] Unexpected panic: %v", i, err)
+				}
+			}()
+			ml.NewMLP(elem.sizes, elem.activations, ml.NewRNG(1))
+		}()
+	}
+}
@@ -0,0 +1,278 @@
+package ml
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// NumPy format errors.
+var (
+	ErrInvalidNPY       = errors.New("invalid npy data")
+	ErrUnsupportedDtype = errors.New("unsupported npy dtype, only float32 and float64 are supported")
+	ErrUnsupportedShape = errors.New("unsupported npy shape, only 0, 1 and 2 dimensions are supported")
+)
+
+// npyMagic is the prefix of all .npy files.
+const npyMagic = "\x93NUMPY"
+
+// npyMaxHeader is the largest header length read, the same limit as NumPy.
+const npyMaxHeader = 10000
+
+var (
+	npyDescrRe   = regexp.MustCompile(`['"]descr['"]\s*:\s*['"]([^'"]*)['"]`)
+	npyFortranRe = regexp.MustCompile(`['"]fortran_order['"]\s*:\s*(True|False)`)
+	npyShapeRe   = regexp.MustCompile(`['"]shape['"]\s*:\s*\(([^)]*)\)`)
+)
+
+// npyHeader is the parsed .npy header.
+type npyHeader struct {
+	order   binary.ByteOrder
+	size    int // Element size in bytes: 4 or 8.
+	fortran bool
+	shape   []int
+}
+
+// readNPYHeader reads the magic, version and header dict from the given reader.
+func readNPYHeader(r io.Reader) (npyHeader, error) {
+	var hdr npyHeader
+
+	prefix := make([]byte, len(npyMagic)+2)
+	if _, err := io.ReadFull(r, prefix); err != nil {
+		return hdr, err
+	}
+	if string(prefix[:len(npyMagic)]) != npyMagic {
+		return hdr, ErrInvalidNPY
+	}
+	var headerLen int
+	switch major := prefix[len(npyMagic)]; major {
+	case 1:
+		var l uint16
+		if err := binary.Read(r, binary.LittleEndian, &l); err != nil {
+			return hdr, err
+		}
+		headerLen = int(l)
+	case 2, 3:
+		var l uint32
+		if err := binary.Read(r, binary.LittleEndian, &l); err != nil {
+			return hdr, err
+		}
+		headerLen = int(l)
+	default:
+		return hdr, fmt.Errorf("%w: unknown version %d", ErrInvalidNPY, major)
+	}
+	if headerLen > npyMaxHeader {
+		return hdr, fmt.Errorf("%w: header length %d", ErrInvalidNPY, headerLen)
+	}
+	buf := make([]byte, headerLen)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return hdr, err
+	}
+	header := string(buf)
+
+	descr := npyDescrRe.FindStringSubmatch(header)
+	fortran := npyFortranRe.FindStringSubmatch(header)
+	shape := npyShapeRe.FindStringSubmatch(header)
+	if descr == nil || fortran == nil || shape == nil {
+		return hdr, ErrInvalidNPY
+	}
+
+	// Dtype: byte order followed by kind and size, i.e. '<f8'.
+	switch d := descr[1]; d {
+	case "<f8", "=f8", "f8":
+		hdr.order, hdr.size = binary.LittleEndian, 8
+	case ">f8":
+		hdr.order, hdr.size = binary.BigEndian, 8
+	case "<f4", "=f4", "f4":
+		hdr.order, hdr.size = binary.LittleEndian, 4
+	case ">f4":
+		hdr.order, hdr.size = binary.BigEndian, 4
+	default:
+		return hdr, fmt.Errorf("%w: %q", ErrUnsupportedDtype, d)
+	}
+	hdr.fortran = fortran[1] == "True"
+
+	for _, dim := range strings.Split(shape[1], ",") {
+		if dim = strings.TrimSpace(dim); dim == "" {
+			continue
+		}
+		n, err := strconv.Atoi(dim)
+		if err != nil || n < 0 {
+			return hdr, ErrInvalidNPY
+		}
+		hdr.shape = append(hdr.shape, n)
+	}
+	if len(hdr.shape) > 2 {
+		return hdr, ErrUnsupportedShape
+	}
+	return hdr, nil
+}
+
+// npyChunk is the number of elements read at once, so the header shape is not
+// trusted to allocate the data before it is read.
+const npyChunk = 4096
+
+// ReadNPY reads a NumPy .npy array from the given reader.
+// Supports float32 and float64 arrays of both byte orders, in C or Fortran order.
+// Scalars are returned as a (1,1) matrix and 1 dimension arrays as a (n,1) column.
+// Returns ErrInvalidNPY for shapes with rows but no columns (or the opposite)
+// and for shapes too large to be addressed.
+func ReadNPY(r io.Reader) (Matrix, error) {
+	hdr, err := readNPYHeader(r)
+	if err != nil {
+		return nil, err
+	}
+	m, n := 1, 1
+	switch len(hdr.shape) {
+	case 1:
+		m = hdr.shape[0]
+	case 2:
+		m, n = hdr.shape[0], hdr.shape[1]
+		if (m == 0) != (n == 0) {
+			return nil, fmt.Errorf("%w: shape (%d, %d)", ErrInvalidNPY, m, n)
+		}
+	}
+	if n != 0 && m > math.MaxInt/hdr.size/n {
+		return nil, fmt.Errorf("%w: shape (%d, %d) too large", ErrInvalidNPY, m, n)
+	}
+
+	data := make([]float64, 0, minInt(m*n, npyChunk))
+	buf := make([]byte, npyChunk*hdr.size)
+	for len(data) < m*n {
+		chunk := buf[:minInt(m*n-len(data), npyChunk)*hdr.size]
+		if _, err := io.ReadFull(r, chunk); err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return nil, err
+		}
+		for k := 0; k < len(chunk); k += hdr.size {
+			if hdr.size == 8 {
+				data = append(data, math.Float64frombits(hdr.order.Uint64(chunk[k:])))
+			} else {
+				data = append(data, float64(math.Float32frombits(hdr.order.Uint32(chunk[k:]))))
+			}
+		}
+	}
+	ret := NewMatrix(m, n)
+	for k, elem := range data {
+		if hdr.fortran {
+			ret[k%m][k/m] = elem
+		} else {
+			ret[k/n][k%n] = elem
+		}
+	}
+	return ret, nil
+}
+
+// minInt returns the smallest of the given integers.
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// ReadNPYVector reads a NumPy .npy array as a vector.
+// The array needs to be 1 dimension or a single column.
+func ReadNPYVector(r io.Reader) (Vector, error) {
+	ma, err := ReadNPY(r)
+	if err != nil {
+		return nil, err
+	}
+	if err := Vector(ma).Validate(); err != nil {
+		return nil, err
+	}
+	return Vector(ma), nil
+}
+
+// writeNPY writes the given matrix as a little-endian float64 C-ordered array of the given shape.
+func writeNPY(w io.Writer, ma Matrix, shape string) error {
+	header := fmt.Sprintf("{'descr': '<f8', 'fortran_order': False, 'shape': %s, }", shape)
+	// Pad with spaces so the data is 64 bytes aligned. The header ends with a newline.
+	prefixLen := len(npyMagic) + 2 + 2
+	header += strings.Repeat(" ", 63-(prefixLen+len(header))%64) + "\n"
+
+	buf := bytes.NewBuffer(nil)
+	buf.WriteString(npyMagic)
+	buf.Write([]byte{1, 0})
+	_ = binary.Write(buf, binary.LittleEndian, uint16(len(header)))
+	buf.WriteString(header)
+	for _, line := range ma {
+		for _, elem := range line {
+			_ = binary.Write(buf, binary.LittleEndian, elem)
+		}
+	}
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+// WriteNPY writes the given matrix as a 2 dimensions float64 NumPy .npy array.
+func WriteNPY(w io.Writer, ma Matrix) error {
+	if err := ma.Validate(); err != nil {
+		return err
+	}
+	m, n := ma.shape()
+	return writeNPY(w, ma, fmt.Sprintf("(%d, %d)", m, n))
+}
+
+// WriteNPYVector writes the given vector as a 1 dimension float64 NumPy .npy array.
+func WriteNPYVector(w io.Writer, v Vector) error {
+	if err := v.Validate(); err != nil {
+		return err
+	}
+	return writeNPY(w, Matrix(v), fmt.Sprintf("(%d,)", len(v)))
+}
+
+// ReadNPZ reads all the arrays of a NumPy .npz archive.
+// The returned map is keyed by array name, without the .npy extension.
+func ReadNPZ(r io.ReaderAt, size int64) (map[string]Matrix, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, err
+	}
+	ret := make(map[string]Matrix, len(zr.File))
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+		ma, err := ReadNPY(rc)
+		_ = rc.Close()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", f.Name, err)
+		}
+		ret[strings.TrimSuffix(f.Name, ".npy")] = ma
+	}
+	return ret, nil
+}
+
+// WriteNPZ writes the given arrays as a NumPy .npz archive.
+// Each matrix is stored as <name>.npy, see WriteNPY.
+func WriteNPZ(w io.Writer, arrays map[string]Matrix) error {
+	names := make([]string, 0, len(arrays))
+	for name := range arrays {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	zw := zip.NewWriter(w)
+	for _, name := range names {
+		f, err := zw.Create(name + ".npy")
+		if err != nil {
+			return err
+		}
+		if err := WriteNPY(f, arrays[name]); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	return zw.Close()
+}
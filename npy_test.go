@@ -0,0 +1,128 @@
+package ml_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/creack/ml"
+)
+
+// npyFile builds a version 1.0 .npy file with the given header dict and raw data.
+func npyFile(header string, data []byte) []byte {
+	buf := bytes.NewBuffer(nil)
+	buf.WriteString("\x93NUMPY\x01\x00")
+	_ = binary.Write(buf, binary.LittleEndian, uint16(len(header)+1))
+	buf.WriteString(header + "\n")
+	buf.Write(data)
+	return buf.Bytes()
+}
+
+func TestNPYRoundTrip(t *testing.T) {
+	m1 := ml.Matrix{
+		{1, 2, 3},
+		{4, 5, -6.5},
+	}
+	buf := bytes.NewBuffer(nil)
+	if err := ml.WriteNPY(buf, m1); err != nil {
+		t.Fatalf("Error writing npy: %s", err)
+	}
+	raw := buf.Bytes()
+	if !bytes.Contains(raw, []byte("'shape': (2, 3)")) {
+		t.Fatalf("Unexpected npy header: %q", raw)
+	}
+	if dataStart := len(raw) - 6*8; dataStart%64 != 0 || raw[dataStart-1] != '\n' {
+		t.Fatalf("npy data is not 64 bytes aligned: %q", raw[:dataStart])
+	}
+	m2, err := ml.ReadNPY(buf)
+	if err != nil {
+		t.Fatalf("Error reading npy: %s", err)
+	}
+	if !m1.Equal(m2) {
+		t.Fatalf("Unexpected npy matrix\nGot:\n%s\nExpect:\n%s\n", m2, m1)
+	}
+
+	v1 := ml.Vector{{1}, {2}, {3}}
+	buf.Reset()
+	if err := ml.WriteNPYVector(buf, v1); err != nil {
+		t.Fatalf("Error writing npy vector: %s", err)
+	}
+	if !bytes.Contains(buf.Bytes(), []byte("'shape': (3,)")) {
+		t.Fatalf("Unexpected npy vector header: %q", buf.Bytes())
+	}
+	v2, err := ml.ReadNPYVector(buf)
+	if err != nil {
+		t.Fatalf("Error reading npy vector: %s", err)
+	}
+	if !ml.Matrix(v1).Equal(ml.Matrix(v2)) {
+		t.Fatalf("Unexpected npy vector\nGot:\n%s\nExpect:\n%s\n", v2, v1)
+	}
+}
+
+func TestNPYFloat32Fortran(t *testing.T) {
+	// Column-major big-endian float32 (2,3) array.
+	data := bytes.NewBuffer(nil)
+	for _, elem := range []float32{1, 4, 2, 5, 3, 6} {
+		_ = binary.Write(data, binary.BigEndian, elem)
+	}
+	raw := npyFile("{'descr': '>f4', 'fortran_order': True, 'shape': (2, 3), }", data.Bytes())
+	ret, err := ml.ReadNPY(bytes.NewReader(raw))
+	if err != nil {
+		t.Fatalf("Error reading npy: %s", err)
+	}
+	if expect := (ml.Matrix{{1, 2, 3}, {4, 5, 6}}); !ret.Equal(expect) {
+		t.Fatalf("Unexpected npy matrix\nGot:\n%s\nExpect:\n%s\n", ret, expect)
+	}
+}
+
+func TestNPYInvalid(t *testing.T) {
+	for i, elem := range []struct {
+		raw    []byte
+		expect error
+	}{
+		{npyFile("{'descr': '<i8', 'fortran_order': False, 'shape': (1,), }", make([]byte, 8)), ml.ErrUnsupportedDtype},
+		{npyFile("{'descr': '<f8', 'fortran_order': False, 'shape': (1, 1, 1), }", make([]byte, 8)), ml.ErrUnsupportedShape},
+		{npyFile("{'descr': '<f8', 'shape': (1,), }", make([]byte, 8)), ml.ErrInvalidNPY},
+		{[]byte("PK\x03\x04 not a npy file"), ml.ErrInvalidNPY},
+		{[]byte("\x93NUMPY\x02\x00\xf0\xff\xff\xff"), ml.ErrInvalidNPY},
+		{npyFile("{'descr': '<f8', 'fortran_order': False, 'shape': (4611686018427387904, 4), }", nil), ml.ErrInvalidNPY},
+		{npyFile("{'descr': '<f8', 'fortran_order': False, 'shape': (3, 0), }", nil), ml.ErrInvalidNPY},
+		{npyFile("{'descr': '<f8', 'fortran_order': False, 'shape': (0, 3), }", nil), ml.ErrInvalidNPY},
+		// The data is read as it comes, the shape does not pre-allocate it.
+		{npyFile("{'descr': '<f8', 'fortran_order': False, 'shape': (100000000, 100), }", make([]byte, 8)), io.ErrUnexpectedEOF},
+		{npyFile("{'descr': '<f8', 'fortran_order': False, 'shape': (2,), }", nil), io.ErrUnexpectedEOF},
+	} {
+		if _, err := ml.ReadNPY(bytes.NewReader(elem.raw)); !errors.Is(err, elem.expect) {
+			t.Errorf("[%d] Unexpected error.\nExpect:\t%v\nGot:\t%v", i, elem.expect, err)
+		}
+	}
+}
+
+func TestNPZRoundTrip(t *testing.T) {
+	arrays := map[string]ml.Matrix{
+		"x":     {{1, 2}, {3, 4}, {5, 6}},
+		"theta": {{0.5}, {-1}},
+	}
+	buf := bytes.NewBuffer(nil)
+	if err := ml.WriteNPZ(buf, arrays); err != nil {
+		t.Fatalf("Error writing npz: %s", err)
+	}
+	ret, err := ml.ReadNPZ(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("Error reading npz: %s", err)
+	}
+	if len(ret) != len(arrays) {
+		t.Fatalf("Unexpected npz array count: %d", len(ret))
+	}
+	for name, expect := range arrays {
+		if got := ret[name]; !got.Equal(expect) {
+			t.Fatalf("Unexpected npz array %q\nGot:\n%s\nExpect:\n%s\n", name, got, expect)
+		}
+	}
+	if _, err := ml.ReadNPZ(strings.NewReader("not a zip"), 9); err == nil {
+		t.Fatal("Expected an error reading an invalid npz")
+	}
+}
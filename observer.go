@@ -0,0 +1,70 @@
+package ml
+
+import (
+	"log"
+	"time"
+)
+
+// Event reports the training progress after an iteration, see Observer.
+type Event struct {
+	Iteration    int           // Number of iterations done.
+	Cost         float64       // Cost after the iteration.
+	Θ            Vector        // Snapshot of the parameters after the iteration.
+	GradientNorm float64       // Max absolute component of the last evaluated gradient.
+	Elapsed      time.Duration // Time since the training started.
+
+	ValidationCost float64 // Validation cost after the iteration, NaN when not evaluated.
+}
+
+// Observer receives the training events, i.e. to plot, log or export metrics.
+// Observers are called synchronously from the training loop.
+type Observer interface {
+	Observe(Event)
+}
+
+// ObserverFunc is a function implementing Observer.
+type ObserverFunc func(Event)
+
+// Observe implements Observer.
+func (f ObserverFunc) Observe(e Event) {
+	f(e)
+}
+
+// LogObserver returns an observer logging each event to the given logger,
+// or to the standard logger when nil.
+func LogObserver(logger *log.Logger) Observer {
+	printf := log.Printf
+	if logger != nil {
+		printf = logger.Printf
+	}
+	return ObserverFunc(func(e Event) {
+		printf("iteration %d: cost %g, gradient norm %g, elapsed %s", e.Iteration, e.Cost, e.GradientNorm, e.Elapsed)
+	})
+}
+
+// notifier dispatches the events every given number of iterations.
+type notifier struct {
+	observers []Observer
+	every     int
+	start     time.Time
+}
+
+func newNotifier(observers []Observer, every int) *notifier {
+	if every <= 0 {
+		every = 1
+	}
+	return &notifier{observers: observers, every: every, start: time.Now()}
+}
+
+// notify sends the given event to the observers, setting the elapsed time.
+// e.Θ is only copied when there is an observer to notify.
+func (n *notifier) notify(e Event) {
+	if len(n.observers) == 0 || e.Iteration%n.every != 0 {
+		return
+	}
+	e.Θ = Vector(Matrix(e.Θ).Copy())
+	e.Elapsed = time.Since(n.start)
+	for _, o := range n.observers {
+		o.Observe(e)
+	}
+}
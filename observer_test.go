@@ -0,0 +1,62 @@
+package ml_test
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"strings"
+	"testing"
+
+	"github.com/creack/ml"
+)
+
+func TestObservers(t *testing.T) {
+	var events []ml.Event
+	collect := ml.ObserverFunc(func(e ml.Event) { events = append(events, e) })
+
+	lr := &ml.LinearRegression{Θ: ml.NewVector(3)}
+	opts := ml.TrainOptions{MaxIterations: 10, Observers: []ml.Observer{collect}, ObserveEvery: 3}
+	res, err := ml.Train(context.Background(), lr, testRegularizationDataset, &ml.SGD{Alpha: 0.02}, opts)
+	if err != nil {
+		t.Fatalf("Error training the model: %s", err)
+	}
+	if len(events) != 3 {
+		t.Fatalf("Unexpected event count: %d", len(events))
+	}
+	for i, e := range events {
+		if e.Iteration != 3*(i+1) || e.Cost != res.History[e.Iteration] || e.GradientNorm <= 0 || e.Elapsed <= 0 {
+			t.Fatalf("[%d] Unexpected event: %+v", i, e)
+		}
+	}
+	// The events hold a snapshot of the parameters.
+	if last := events[len(events)-1].Θ; ml.Matrix(last).Equal(ml.Matrix(lr.Θ)) {
+		t.Fatalf("Parameters snapshot changed with the model: %v", last)
+	}
+}
+
+func TestLogObserver(t *testing.T) {
+	buf := &bytes.Buffer{}
+	θ := ml.Vector{{-1.2}, {1}}
+	cost, gradient := rosenbrock(θ)
+	opts := ml.QuasiNewtonOptions{MaxIterations: 2, Observers: []ml.Observer{ml.LogObserver(log.New(buf, "", 0))}}
+	if _, err := ml.BFGS(context.Background(), θ, cost, gradient, ml.Dataset{}, opts); err != nil {
+		t.Fatalf("Error minimizing: %s", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[0], "iteration 1: cost ") || !strings.HasPrefix(lines[1], "iteration 2: cost ") {
+		t.Fatalf("Unexpected log:\n%s", buf)
+	}
+}
+
+func TestGradientDescentObserver(t *testing.T) {
+	iterations := 0
+	lr := &ml.LogisticRegression{Θ: ml.NewVector(2)}
+	opts := ml.GradientDescentOptions()
+	opts.Observers = []ml.Observer{ml.ObserverFunc(func(e ml.Event) { iterations = e.Iteration })}
+	if _, err := lr.GradientDescent(context.Background(), testLogisticDataset, 0.5, opts); err != nil {
+		t.Fatalf("Error training the model: %s", err)
+	}
+	if iterations == 0 {
+		t.Fatal("Observer not notified")
+	}
+}
@@ -0,0 +1,449 @@
+package ml
+
+import (
+	"context"
+	"fmt"
+	"math"
+)
+
+// Model is a differentiable model, trainable with Train.
+type Model interface {
+	Parameters() Vector              // Trained parameters. NOTE: Not a copy, changes to the vector affect the model.
+	Cost(dataset Dataset) float64    // Cost function minimized by the training.
+	Gradient(dataset Dataset) Vector // Gradient of the cost, in the Parameters order.
+}
+
+// Optimizer updates the parameters from the cost gradient.
+type Optimizer interface {
+	// Step updates θ in place given the gradient of the cost at θ.
+	Step(θ, grad Vector)
+}
+
+// SGD is the plain gradient descent update: θ = θ - α * ∇.
+// Batch gradient descent when trained on the full dataset, stochastic
+// gradient descent when trained on samples.
+type SGD struct {
+	Alpha float64 // Learning rate.
+}
+
+// Step implements Optimizer.
+func (o *SGD) Step(θ, grad Vector) {
+	for j := range θ {
+		θ[j][0] -= o.Alpha * grad[j][0]
+	}
+}
+
+// Momentum is gradient descent with momentum:
+// v = β * v - α * ∇, θ = θ + v.
+type Momentum struct {
+	Alpha float64 // Learning rate.
+	Beta  float64 // Momentum decay, i.e. 0.9.
+
+	Velocity Vector // State, initialized on the first step.
+}
+
+// Step implements Optimizer.
+func (o *Momentum) Step(θ, grad Vector) {
+	o.Velocity = state(o.Velocity, len(θ))
+	for j := range θ {
+		o.Velocity[j][0] = o.Beta*o.Velocity[j][0] - o.Alpha*grad[j][0]
+		θ[j][0] += o.Velocity[j][0]
+	}
+}
+
+// Nesterov is gradient descent with Nesterov accelerated momentum.
+// Uses the look-ahead reformulation evaluating the gradient at θ:
+// v = β * v - α * ∇, θ = θ + β * v - α * ∇.
+type Nesterov struct {
+	Alpha float64 // Learning rate.
+	Beta  float64 // Momentum decay, i.e. 0.9.
+
+	Velocity Vector // State, initialized on the first step.
+}
+
+// Step implements Optimizer.
+func (o *Nesterov) Step(θ, grad Vector) {
+	o.Velocity = state(o.Velocity, len(θ))
+	for j := range θ {
+		o.Velocity[j][0] = o.Beta*o.Velocity[j][0] - o.Alpha*grad[j][0]
+		θ[j][0] += o.Beta*o.Velocity[j][0] - o.Alpha*grad[j][0]
+	}
+}
+
+// AdaGrad scales the learning rate of each parameter by its gradient history:
+// G = G + ∇², θ = θ - α * ∇ / (√G + ε).
+type AdaGrad struct {
+	Alpha   float64 // Learning rate.
+	Epsilon float64 // Numerical stability term, defaults to 1e-8.
+
+	SquaredSum Vector // State, initialized on the first step.
+}
+
+// Step implements Optimizer.
+func (o *AdaGrad) Step(θ, grad Vector) {
+	o.SquaredSum = state(o.SquaredSum, len(θ))
+	eps := defaultFloat(o.Epsilon, 1e-8)
+	for j := range θ {
+		g := grad[j][0]
+		o.SquaredSum[j][0] += g * g
+		θ[j][0] -= o.Alpha * g / (math.Sqrt(o.SquaredSum[j][0]) + eps)
+	}
+}
+
+// RMSProp scales the learning rate of each parameter by a moving average
+// of its squared gradient:
+// E = ρ * E + (1 - ρ) * ∇², θ = θ - α * ∇ / (√E + ε).
+type RMSProp struct {
+	Alpha   float64 // Learning rate.
+	Rho     float64 // Moving average decay, defaults to 0.9.
+	Epsilon float64 // Numerical stability term, defaults to 1e-8.
+
+	SquaredAverage Vector // State, initialized on the first step.
+}
+
+// Step implements Optimizer.
+func (o *RMSProp) Step(θ, grad Vector) {
+	o.SquaredAverage = state(o.SquaredAverage, len(θ))
+	rho, eps := defaultFloat(o.Rho, 0.9), defaultFloat(o.Epsilon, 1e-8)
+	for j := range θ {
+		g := grad[j][0]
+		o.SquaredAverage[j][0] = rho*o.SquaredAverage[j][0] + (1-rho)*g*g
+		θ[j][0] -= o.Alpha * g / (math.Sqrt(o.SquaredAverage[j][0]) + eps)
+	}
+}
+
+// Adam is the adaptive moment estimation optimizer:
+// m = β1 * m + (1 - β1) * ∇, v = β2 * v + (1 - β2) * ∇²,
+// θ = θ - α * m̂ / (√v̂ + ε) with m̂, v̂ the bias-corrected moments.
+type Adam struct {
+	Alpha   float64 // Learning rate.
+	Beta1   float64 // First moment decay, defaults to 0.9.
+	Beta2   float64 // Second moment decay, defaults to 0.999.
+	Epsilon float64 // Numerical stability term, defaults to 1e-8.
+
+	// State, initialized on the first step.
+	M, V Vector
+	T    int
+}
+
+// Step implements Optimizer.
+func (o *Adam) Step(θ, grad Vector) {
+	o.M, o.V = state(o.M, len(θ)), state(o.V, len(θ))
+	beta1, beta2 := defaultFloat(o.Beta1, 0.9), defaultFloat(o.Beta2, 0.999)
+	eps := defaultFloat(o.Epsilon, 1e-8)
+	o.T++
+	c1, c2 := 1-math.Pow(beta1, float64(o.T)), 1-math.Pow(beta2, float64(o.T))
+	for j := range θ {
+		g := grad[j][0]
+		o.M[j][0] = beta1*o.M[j][0] + (1-beta1)*g
+		o.V[j][0] = beta2*o.V[j][0] + (1-beta2)*g*g
+		θ[j][0] -= o.Alpha * (o.M[j][0] / c1) / (math.Sqrt(o.V[j][0]/c2) + eps)
+	}
+}
+
+// state returns the given optimizer state, or a zero one if it does not
+// match the parameter count.
+func state(v Vector, n int) Vector {
+	if len(v) != n {
+		return NewVector(n)
+	}
+	return v
+}
+
+// defaultFloat returns x, or def when x is not set.
+func defaultFloat(x, def float64) float64 {
+	if x == 0 {
+		return def
+	}
+	return x
+}
+
+// Termination is the reason a training stopped.
+type Termination int
+
+// Termination reasons.
+const (
+	TerminationMaxIterations       Termination = iota // The iteration limit was reached.
+	TerminationGradientTolerance                      // The gradient norm went below the tolerance.
+	TerminationLineSearchFailed                       // No step satisfying the Wolfe conditions was found.
+	TerminationCostTolerance                          // The cost went below the tolerance.
+	TerminationCostChangeTolerance                    // The relative cost change went below the tolerance.
+	TerminationParameterTolerance                     // The relative parameter change went below the tolerance.
+	TerminationCanceled                               // The context was canceled or its deadline exceeded.
+	TerminationEarlyStopping                          // The validation cost stopped improving.
+)
+
+func (t Termination) String() string {
+	switch t {
+	case TerminationMaxIterations:
+		return "max iterations"
+	case TerminationGradientTolerance:
+		return "gradient tolerance"
+	case TerminationLineSearchFailed:
+		return "line search failed"
+	case TerminationCostTolerance:
+		return "cost tolerance"
+	case TerminationCostChangeTolerance:
+		return "cost change tolerance"
+	case TerminationParameterTolerance:
+		return "parameter tolerance"
+	case TerminationCanceled:
+		return "canceled"
+	case TerminationEarlyStopping:
+		return "early stopping"
+	}
+	return fmt.Sprintf("Termination(%d)", int(t))
+}
+
+// TrainOptions configures Train.
+// The tolerances are checked at each iteration, a zero tolerance disables its check.
+type TrainOptions struct {
+	MaxIterations int  // Max number of iterations (passes over the dataset), defaults to 1000.
+	BatchSize     int  // Samples per step: 0 or m for batch, 1 for stochastic, mini-batch otherwise.
+	Rand          *RNG // Shuffles the dataset at each iteration for mini-batches, defaults to NewRNG(0).
+
+	// Schedule sets the learning rate at each iteration.
+	// The optimizer needs to implement ScheduledOptimizer.
+	Schedule Schedule
+
+	CostTolerance       float64 // Stops when the cost goes below it.
+	CostChangeTolerance float64 // Stops when |Δcost| <= tolerance * |cost|.
+	GradientTolerance   float64 // Stops when the max absolute gradient component goes below it.
+	ParameterTolerance  float64 // Stops when max |Δθ| <= tolerance * max(1, max |θ|).
+
+	Observers    []Observer // Notified of the training progress.
+	ObserveEvery int        // Notifies the observers every given number of iterations, defaults to 1.
+
+	// Validation is a held-out dataset evaluated every ValidateEvery iterations (defaults to 1).
+	// When set, the parameters with the lowest validation cost are restored at the end
+	// and the training stops early after Patience evaluations without improvement
+	// (0 to never stop early).
+	Validation    Dataset
+	ValidateEvery int
+	Patience      int
+
+	// CheckpointPath is the file where the training state is saved every
+	// CheckpointEvery iterations (defaults to 1), see Resume.
+	// The optimizer needs to be registered, see RegisterOptimizer.
+	CheckpointPath  string
+	CheckpointEvery int
+}
+
+// TrainResult reports a training.
+type TrainResult struct {
+	Iterations  int         // Number of iterations.
+	Evaluations int         // Number of gradient evaluations.
+	Cost        float64     // Final cost.
+	History     []float64   // Cost before the first iteration and after each one.
+	Reason      Termination // Why the training stopped.
+
+	ValidationHistory []float64 // Validation cost before the first iteration and at each evaluation.
+	ValidationCost    float64   // Lowest validation cost, the one of the final parameters.
+	BestIteration     int       // Iteration of the lowest validation cost.
+}
+
+// GradientDescentOptions returns the default options of the GradientDescent methods:
+// run until the parameters no longer significantly change.
+func GradientDescentOptions() TrainOptions {
+	return TrainOptions{MaxIterations: 1e9, CostTolerance: 1e-20, ParameterTolerance: 1e-13}
+}
+
+// Train minimizes the model cost on the given dataset with the given optimizer.
+// Each iteration steps once per batch, the dataset being reshuffled first
+// unless trained on the full batch.
+// Stops on the first tolerance reached, see TrainOptions.
+// With a validation dataset, the parameters with the lowest validation cost are
+// restored at the end.
+// Stops with ctx.Err() when the context is done, the parameters being restored
+// to the lowest (validation) cost found so far.
+// Aborts with a *NonFiniteError when the parameters diverge.
+func Train(ctx context.Context, model Model, dataset Dataset, opt Optimizer, opts TrainOptions) (TrainResult, error) {
+	return train(ctx, model, dataset, opt, opts, nil)
+}
+
+// train implements Train, resuming from the given checkpoint when not nil.
+func train(ctx context.Context, model Model, dataset Dataset, opt Optimizer, opts TrainOptions, cp *Checkpoint) (res TrainResult, err error) {
+	defer recoverNonFinite(&err)
+
+	m, _ := dataset.X.Dim()
+	batchSize := opts.BatchSize
+	if batchSize <= 0 || batchSize > m {
+		batchSize = m
+	}
+	maxIter := opts.MaxIterations
+	if maxIter <= 0 {
+		maxIter = 1000
+	}
+	rnd := opts.Rand
+	if rnd == nil {
+		rnd = NewRNG(0)
+	}
+	idx := make([]int, m)
+	checkpointEvery := opts.CheckpointEvery
+	if checkpointEvery <= 0 {
+		checkpointEvery = 1
+	}
+
+	θ := model.Parameters()
+	scheduled, ok := opt.(ScheduledOptimizer)
+	if opts.Schedule != nil && !ok {
+		return res, ErrNoLearningRate
+	}
+	step := func(batch Dataset, grad Vector) {
+		if ls, ok := opt.(costOptimizer); ok {
+			ls.setCost(func() float64 { return model.Cost(batch) })
+		}
+		opt.Step(θ, grad)
+	}
+
+	n := newNotifier(opts.Observers, opts.ObserveEvery)
+	v := newValidator(opts.Validation, opts.ValidateEvery, opts.Patience)
+	best, bestCost := Matrix(θ).Copy(), math.Inf(1)
+	cost := func() (float64, error) {
+		c := model.Cost(dataset)
+		if !isFinite(c) || !θ.IsFinite() {
+			return c, fmt.Errorf("iteration %d: %w", res.Iterations, &NonFiniteError{Op: "Train", Row: -1, Col: -1, Value: c})
+		}
+		if c < bestCost {
+			best.SetSubMatrix(Matrix(θ), 0, 0)
+			bestCost = c
+		}
+		res.Cost = c
+		res.History = append(res.History, c)
+		return c, nil
+	}
+	canceled := func() (TrainResult, error) {
+		Matrix(θ).SetSubMatrix(best, 0, 0)
+		res.Cost, res.Reason = bestCost, TerminationCanceled
+		return res, ctx.Err()
+	}
+
+	if cp != nil {
+		// The checkpointed cost is evaluated again below.
+		res.Iterations, res.Evaluations = cp.Iterations, cp.Evaluations
+		if n := len(cp.History); n > 0 {
+			res.History = cp.History[:n-1]
+		}
+		res.ValidationHistory, res.ValidationCost, res.BestIteration = cp.ValidationHistory, cp.ValidationCost, cp.BestIteration
+		if v != nil && cp.BestParameters != nil {
+			v.best, v.bestCost, v.sinceBest = Matrix(cp.BestParameters), cp.BestCost, cp.SinceBest
+		}
+	}
+	c, err := cost()
+	if err != nil {
+		return res, err
+	}
+	if cp == nil {
+		v.validate(model, &res)
+	}
+	defer v.restore(model, &res)
+
+	res.Reason = TerminationMaxIterations
+	for res.Iterations < maxIter {
+		if ctx.Err() != nil {
+			return canceled()
+		}
+		if opts.CostTolerance > 0 && c <= opts.CostTolerance {
+			res.Reason = TerminationCostTolerance
+			break
+		}
+		if opts.Schedule != nil {
+			scheduled.SetLearningRate(opts.Schedule.Rate(res.Iterations))
+		}
+
+		prev := Matrix(θ).Copy()
+		var gradNorm float64
+		if batchSize == m {
+			grad := model.Gradient(dataset)
+			res.Evaluations++
+			if gradNorm = Matrix(grad).normInf(); opts.GradientTolerance > 0 && gradNorm <= opts.GradientTolerance {
+				res.Reason = TerminationGradientTolerance
+				break
+			}
+			step(dataset, grad)
+		} else {
+			if opts.GradientTolerance > 0 {
+				res.Evaluations++
+				if Matrix(model.Gradient(dataset)).normInf() <= opts.GradientTolerance {
+					res.Reason = TerminationGradientTolerance
+					break
+				}
+			}
+			for i := range idx {
+				idx[i] = i
+			}
+			rnd.Shuffle(idx)
+			for start := 0; start < m; start += batchSize {
+				if ctx.Err() != nil {
+					return canceled()
+				}
+				end := start + batchSize
+				if end > m {
+					end = m
+				}
+				batch := dataset.subset(idx[start:end])
+				grad := model.Gradient(batch)
+				res.Evaluations++
+				gradNorm = Matrix(grad).normInf()
+				step(batch, grad)
+			}
+		}
+		res.Iterations++
+
+		prevC := c
+		if c, err = cost(); err != nil {
+			return res, err
+		}
+		vc, stop := v.validate(model, &res)
+		n.notify(Event{Iteration: res.Iterations, Cost: c, Θ: θ, GradientNorm: gradNorm, ValidationCost: vc})
+		if opts.CheckpointPath != "" && res.Iterations%checkpointEvery == 0 {
+			cp, err := newCheckpoint(θ, opt, rnd, res, v)
+			if err != nil {
+				return res, err
+			}
+			if err := cp.save(opts.CheckpointPath); err != nil {
+				return res, err
+			}
+		}
+		if stop {
+			res.Reason = TerminationEarlyStopping
+			break
+		}
+		change, norm := 0., 1.
+		for j := range θ {
+			change = math.Max(change, math.Abs(θ[j][0]-prev[j][0]))
+			norm = math.Max(norm, math.Abs(θ[j][0]))
+		}
+		if opts.ParameterTolerance > 0 && change <= opts.ParameterTolerance*norm {
+			res.Reason = TerminationParameterTolerance
+			break
+		}
+		if opts.CostChangeTolerance > 0 && math.Abs(c-prevC) <= opts.CostChangeTolerance*math.Abs(prevC) {
+			res.Reason = TerminationCostChangeTolerance
+			break
+		}
+	}
+	return res, nil
+}
+
+// subset returns the dataset restricted to the given rows.
+// NOTE: Not a copy, the rows are shared with the current dataset.
+func (ds Dataset) subset(rows []int) Dataset {
+	ret := Dataset{X: make(Matrix, len(rows))}
+	if len(ds.Y) > 0 {
+		ret.Y = make(Vector, len(rows))
+	}
+	if len(ds.Targets) > 0 {
+		ret.Targets = make(Matrix, len(rows))
+	}
+	for i, row := range rows {
+		ret.X[i] = ds.X[row]
+		if ret.Y != nil {
+			ret.Y[i] = ds.Y[row]
+		}
+		if ret.Targets != nil {
+			ret.Targets[i] = ds.Targets[row]
+		}
+	}
+	return ret
+}
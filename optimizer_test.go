@@ -0,0 +1,222 @@
+package ml_test
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/creack/ml"
+)
+
+// Models trainable with ml.Train.
+var (
+	_ ml.Model = ml.LinearRegression{}
+	_ ml.Model = ml.LogisticRegression{}
+	_ ml.Model = ml.SoftmaxRegression{}
+	_ ml.Model = ml.MLP{}
+)
+
+func TestOptimizers(t *testing.T) {
+	optimum := &ml.LinearRegression{Θ: ml.NewVector(3)}
+	if err := optimum.NormalEquation(testRegularizationDataset); err != nil {
+		t.Fatalf("Error solving the normal equation: %s", err)
+	}
+	best := optimum.Cost(testRegularizationDataset)
+
+	for _, elem := range []struct {
+		name       string
+		opt        ml.Optimizer
+		iterations int
+	}{
+		{"sgd", &ml.SGD{Alpha: 0.05}, 20000},
+		{"momentum", &ml.Momentum{Alpha: 0.01, Beta: 0.9}, 5000},
+		{"nesterov", &ml.Nesterov{Alpha: 0.01, Beta: 0.9}, 5000},
+		{"adagrad", &ml.AdaGrad{Alpha: 1}, 20000},
+		{"rmsprop", &ml.RMSProp{Alpha: 0.001}, 20000},
+		{"adam", &ml.Adam{Alpha: 0.05}, 10000},
+	} {
+		lr := &ml.LinearRegression{Θ: ml.NewVector(3)}
+		if _, err := ml.Train(context.Background(), lr, testRegularizationDataset, elem.opt, ml.TrainOptions{MaxIterations: elem.iterations, ParameterTolerance: 1e-13}); err != nil {
+			t.Fatalf("%s: error training the model: %s", elem.name, err)
+		}
+		if cost := lr.Cost(testRegularizationDataset); cost-best > 1e-4 {
+			t.Errorf("%s: cost too far from the optimum: %g > %g (%v)", elem.name, cost, best, lr.Θ)
+		}
+	}
+}
+
+func TestAdamStep(t *testing.T) {
+	// The first bias-corrected Adam step is α * sign(∇).
+	opt := &ml.Adam{Alpha: 0.1}
+	θ := ml.Vector{{1}, {1}, {1}}
+	opt.Step(θ, ml.Vector{{3}, {-0.01}, {0}})
+	for i, expect := range []float64{0.9, 1.1, 1} {
+		if math.Abs(θ[i][0]-expect) > 1e-6 {
+			t.Fatalf("Unexpected Θ[%d] after the first step.\nExpect:\t%g\nGot:\t%g", i, expect, θ[i][0])
+		}
+	}
+	if opt.T != 1 || len(opt.M) != 3 || len(opt.V) != 3 {
+		t.Fatalf("Unexpected optimizer state: %d %v %v", opt.T, opt.M, opt.V)
+	}
+}
+
+func TestTrainMLP(t *testing.T) {
+	nn := ml.NewMLP([]int{2, 4, 1}, []ml.Activation{ml.ActivationTanh, ml.ActivationSigmoid}, ml.NewRNG(1))
+	if _, err := ml.Train(context.Background(), nn, testXORDataset, &ml.Adam{Alpha: 0.05}, ml.TrainOptions{MaxIterations: 2000}); err != nil {
+		t.Fatalf("Error training the network: %s", err)
+	}
+	if cost := nn.Cost(testXORDataset); cost > 0.05 {
+		t.Fatalf("Network did not fit XOR, cost: %g", cost)
+	}
+}
+
+func TestTrainDiverge(t *testing.T) {
+	lr := &ml.LinearRegression{Θ: ml.NewVector(3)}
+	_, err := ml.Train(context.Background(), lr, testRegularizationDataset, &ml.SGD{Alpha: 10}, ml.TrainOptions{MaxIterations: 1000})
+	var nf *ml.NonFiniteError
+	if !errors.As(err, &nf) || nf.Op != "Train" {
+		t.Fatalf("Unexpected error for a diverging training: %v", err)
+	}
+}
+
+func TestTrainMiniBatch(t *testing.T) {
+	optimum := &ml.LinearRegression{Θ: ml.NewVector(3)}
+	if err := optimum.NormalEquation(testRegularizationDataset); err != nil {
+		t.Fatalf("Error solving the normal equation: %s", err)
+	}
+	best := optimum.Cost(testRegularizationDataset)
+
+	for _, batchSize := range []int{1, 2, 3} {
+		var results []ml.Vector
+		for run := 0; run < 2; run++ {
+			lr := &ml.LinearRegression{Θ: ml.NewVector(3)}
+			opts := ml.TrainOptions{MaxIterations: 3000, BatchSize: batchSize, Rand: ml.NewRNG(7)}
+			if _, err := ml.Train(context.Background(), lr, testRegularizationDataset, &ml.Adam{Alpha: 0.01}, opts); err != nil {
+				t.Fatalf("[%d] Error training the model: %s", batchSize, err)
+			}
+			if cost := lr.Cost(testRegularizationDataset); cost-best > 1e-3 {
+				t.Errorf("[%d] Cost too far from the optimum: %g > %g", batchSize, cost, best)
+			}
+			results = append(results, lr.Θ)
+		}
+		// Same seed, same result.
+		if !ml.Matrix(results[0]).Equal(ml.Matrix(results[1])) {
+			t.Fatalf("[%d] Training is not reproducible: %v != %v", batchSize, results[0], results[1])
+		}
+	}
+}
+
+func TestSinglePassGradient(t *testing.T) {
+	lr := ml.LinearRegression{Θ: ml.Vector{{0.1}, {-0.2}, {0.3}}, Lambda: 0.5, L1Ratio: 0.5}
+	lg := ml.LogisticRegression{Θ: ml.Vector{{-1}, {0.5}}}
+	for _, elem := range []struct {
+		grad    ml.Vector
+		partial func(j int) float64
+	}{
+		{lr.Gradient(testRegularizationDataset), func(j int) float64 { return lr.PartialDerivative(testRegularizationDataset, j) }},
+		{lg.Gradient(testLogisticDataset), func(j int) float64 { return lg.PartialDerivative(testLogisticDataset, j) }},
+	} {
+		for j := range elem.grad {
+			if expect, got := stringify(elem.partial(j)), stringify(elem.grad[j][0]); expect != got {
+				t.Fatalf("Unexpected gradient for Θ[%d].\nExpect:\t%s\nGot:\t%s", j, expect, got)
+			}
+		}
+	}
+}
+
+func TestTrainTermination(t *testing.T) {
+	for _, elem := range []struct {
+		opts   ml.TrainOptions
+		expect ml.Termination
+	}{
+		{ml.TrainOptions{MaxIterations: 10}, ml.TerminationMaxIterations},
+		{ml.TrainOptions{CostTolerance: 0.05}, ml.TerminationCostTolerance},
+		{ml.TrainOptions{MaxIterations: 1e6, CostChangeTolerance: 1e-9}, ml.TerminationCostChangeTolerance},
+		{ml.TrainOptions{MaxIterations: 1e6, GradientTolerance: 1e-3}, ml.TerminationGradientTolerance},
+		{ml.TrainOptions{MaxIterations: 1e6, GradientTolerance: 1e-2, BatchSize: 2}, ml.TerminationGradientTolerance},
+		{ml.TrainOptions{MaxIterations: 1e6, ParameterTolerance: 1e-10}, ml.TerminationParameterTolerance},
+	} {
+		lr := &ml.LinearRegression{Θ: ml.NewVector(3)}
+		res, err := ml.Train(context.Background(), lr, testRegularizationDataset, &ml.SGD{Alpha: 0.02}, elem.opts)
+		if err != nil {
+			t.Fatalf("[%s] Error training the model: %s", elem.expect, err)
+		}
+		if res.Reason != elem.expect {
+			t.Errorf("Unexpected termination.\nExpect:\t%s\nGot:\t%s", elem.expect, res.Reason)
+		}
+		if len(res.History) != res.Iterations+1 || res.History[len(res.History)-1] != res.Cost {
+			t.Errorf("[%s] Inconsistent history: %d iterations, %d entries", elem.expect, res.Iterations, len(res.History))
+		}
+		if expect, got := stringify(lr.Cost(testRegularizationDataset)), stringify(res.Cost); expect != got {
+			t.Errorf("[%s] Unexpected final cost.\nExpect:\t%s\nGot:\t%s", elem.expect, expect, got)
+		}
+		if res.Evaluations < res.Iterations {
+			t.Errorf("[%s] Unexpected evaluation count: %+v", elem.expect, res)
+		}
+		// With a small enough step, the cost decreases at each iteration.
+		for i := 1; elem.opts.BatchSize == 0 && i < len(res.History); i++ {
+			if res.History[i] > res.History[i-1]*(1+1e-12) {
+				t.Fatalf("[%s] Cost increased at iteration %d: %g > %g", elem.expect, i, res.History[i], res.History[i-1])
+			}
+		}
+	}
+}
+
+// cancelModel cancels the training context after the given number of gradient evaluations.
+type cancelModel struct {
+	*ml.LinearRegression
+	cancel func()
+	after  int
+}
+
+func (m *cancelModel) Gradient(dataset ml.Dataset) ml.Vector {
+	if m.after--; m.after == 0 {
+		m.cancel()
+	}
+	return m.LinearRegression.Gradient(dataset)
+}
+
+func TestTrainCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Diverging training: the best parameters are the initial ones.
+	model := &cancelModel{LinearRegression: &ml.LinearRegression{Θ: ml.NewVector(3)}, cancel: cancel, after: 5}
+	initial := model.Cost(testRegularizationDataset)
+	res, err := ml.Train(ctx, model, testRegularizationDataset, &ml.SGD{Alpha: 0.2}, ml.TrainOptions{BatchSize: 2})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if res.Reason != ml.TerminationCanceled || res.Evaluations != 5 {
+		t.Fatalf("Unexpected result: %+v", res)
+	}
+	if !ml.Matrix(model.Θ).Equal(ml.NewMatrix(3, 1)) || res.Cost != initial {
+		t.Fatalf("Best parameters not restored: %v (cost %g)", model.Θ, res.Cost)
+	}
+}
+
+func TestTrainDeadline(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	lr := &ml.LinearRegression{Θ: ml.NewVector(3)}
+	initial := lr.Cost(testRegularizationDataset)
+	res, err := ml.Train(ctx, lr, testRegularizationDataset, &ml.SGD{Alpha: 1e-9}, ml.TrainOptions{MaxIterations: 1e12})
+	if !errors.Is(err, context.DeadlineExceeded) || res.Reason != ml.TerminationCanceled {
+		t.Fatalf("Unexpected result: %+v, %v", res, err)
+	}
+	if cost := lr.Cost(testRegularizationDataset); cost >= initial || cost != res.Cost {
+		t.Fatalf("Unexpected cost after the deadline: %g (initial %g, reported %g)", cost, initial, res.Cost)
+	}
+
+	// Already done context: no training at all.
+	res, err = ml.LBFGS(ctx, lr.Θ, lr.SquaredError, lr.Gradient, testRegularizationDataset, ml.QuasiNewtonOptions{})
+	if !errors.Is(err, context.DeadlineExceeded) || res.Iterations != 0 {
+		t.Fatalf("Unexpected result: %+v, %v", res, err)
+	}
+	if err := lr.CoordinateDescent(ctx, testRegularizationDataset, 10, 0); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+}
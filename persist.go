@@ -0,0 +1,328 @@
+package ml
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+)
+
+// Model persistence errors.
+var (
+	ErrUnsupportedModel = errors.New("unsupported model format version")
+	ErrUnknownModel     = errors.New("unknown model kind")
+	ErrInvalidModel     = errors.New("invalid model")
+)
+
+// modelVersion is the current version of the model format.
+//   - 1: initial version.
+const modelVersion = 1
+
+// PersistentModel is a model which can be saved, see SaveModel and RegisterModel.
+// The model state needs to be stored in exported fields, encoded as JSON.
+// Models implementing ValidatedModel are validated when saved and loaded.
+type PersistentModel interface {
+	Features() int // Number of input features, excluding the bias.
+}
+
+// ValidatedModel is a model which can check its state, i.e. its parameter shapes.
+type ValidatedModel interface {
+	Validate() error
+}
+
+// ModelHeader describes a saved model.
+type ModelHeader struct {
+	Kind     string `json:"kind"`    // See RegisterModel.
+	Version  int    `json:"version"` // Format version.
+	Features int    `json:"features"`
+
+	// Preprocessing applied to the features before the model, if any.
+	Preprocessing *Preprocessing `json:"preprocessing,omitempty"`
+}
+
+// savedModel is the saved model format.
+type savedModel struct {
+	Header ModelHeader     `json:"header"`
+	Model  json.RawMessage `json:"model"`
+}
+
+// Preprocessing is the standardization of the features:
+// x'_j = (x_j - Mean[j]) / Scale[j].
+// Models trained on preprocessed features expect the same preprocessing for inference.
+type Preprocessing struct {
+	Mean  []float64 `json:"mean"`
+	Scale []float64 `json:"scale"`
+}
+
+// Standardize returns the preprocessing giving each X column a zero mean and
+// a unit standard deviation. Constant columns are only centered.
+func Standardize(x Matrix) *Preprocessing {
+	m, n := x.Dim()
+	ret := &Preprocessing{Mean: make([]float64, n), Scale: make([]float64, n)}
+	for j := 0; j < n; j++ {
+		var sum Accumulator
+		for i := 0; i < m; i++ {
+			sum.Add(x[i][j])
+		}
+		mean := sum.Sum() / float64(m)
+		var variance Accumulator
+		for i := 0; i < m; i++ {
+			variance.Add((x[i][j] - mean) * (x[i][j] - mean))
+		}
+		ret.Mean[j], ret.Scale[j] = mean, math.Sqrt(variance.Sum()/float64(m))
+		if ret.Scale[j] == 0 {
+			ret.Scale[j] = 1
+		}
+	}
+	return ret
+}
+
+// Apply returns the dataset with preprocessed features.
+// panic with ErrBadDim if the feature count does not match.
+// NOTE: Does not change the current dataset state.
+func (p *Preprocessing) Apply(ds Dataset) Dataset {
+	m, n := ds.X.Dim()
+	if n != len(p.Mean) {
+		panic(ErrBadDim)
+	}
+	x := NewMatrix(m, n)
+	for i, line := range ds.X {
+		for j, elem := range line {
+			x[i][j] = (elem - p.Mean[j]) / p.Scale[j]
+		}
+	}
+	ds.X = x
+	return ds
+}
+
+// ApplyVector returns the preprocessed features of a single (n,1) example.
+// NOTE: Does not change the current vector state.
+func (p *Preprocessing) ApplyVector(x Vector) Vector {
+	return Vector(p.Apply(Dataset{X: Matrix(x).Transpose()}).X.Transpose())
+}
+
+// models is the model registry, by kind and by type.
+var (
+	models     = map[string]func() PersistentModel{}
+	modelKinds = map[reflect.Type]string{}
+)
+
+// RegisterModel registers a model kind so it can be loaded with LoadModel.
+// newModel returns a new zero model, a pointer.
+func RegisterModel(kind string, newModel func() PersistentModel) {
+	models[kind] = newModel
+	modelKinds[reflect.TypeOf(newModel())] = kind
+}
+
+func init() {
+	RegisterModel("linear_regression", func() PersistentModel { return &LinearRegression{} })
+	RegisterModel("logistic_regression", func() PersistentModel { return &LogisticRegression{} })
+	RegisterModel("softmax_regression", func() PersistentModel { return &SoftmaxRegression{} })
+	RegisterModel("mlp", func() PersistentModel { return &MLP{} })
+}
+
+// modelKind returns the registered kind of the given model, a value or a pointer.
+func modelKind(model PersistentModel) (string, error) {
+	t := reflect.TypeOf(model)
+	if t.Kind() != reflect.Pointer {
+		t = reflect.PointerTo(t)
+	}
+	kind, ok := modelKinds[t]
+	if !ok {
+		return "", fmt.Errorf("%w: %T", ErrUnknownModel, model)
+	}
+	return kind, nil
+}
+
+// SaveModel writes the model as JSON, with its header.
+// pre is the preprocessing the model was trained with, nil for none.
+// Models implementing ValidatedModel are validated before writing.
+func SaveModel(w io.Writer, model PersistentModel, pre *Preprocessing) error {
+	kind, err := modelKind(model)
+	if err != nil {
+		return err
+	}
+	if vm, ok := model.(ValidatedModel); ok {
+		if err := vm.Validate(); err != nil {
+			return fmt.Errorf("invalid %s model: %w", kind, err)
+		}
+	}
+	if pre != nil && (len(pre.Mean) != model.Features() || len(pre.Scale) != model.Features()) {
+		return fmt.Errorf("preprocessing for %d features, model has %d: %w", len(pre.Mean), model.Features(), ErrBadDim)
+	}
+	state, err := json.Marshal(model)
+	if err != nil {
+		return err
+	}
+	buf, err := json.Marshal(savedModel{
+		Header: ModelHeader{
+			Kind:          kind,
+			Version:       modelVersion,
+			Features:      model.Features(),
+			Preprocessing: pre,
+		},
+		Model: state,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(buf, '\n'))
+	return err
+}
+
+// LoadModel reads a model written by SaveModel, without knowing its kind
+// in advance. The returned model is a pointer, e.g. *LinearRegression.
+// Only the current format version is supported, other versions fail with
+// ErrUnsupportedModel.
+func LoadModel(r io.Reader) (PersistentModel, ModelHeader, error) {
+	saved, err := readModel(r)
+	if err != nil {
+		return nil, ModelHeader{}, err
+	}
+	newModel, ok := models[saved.Header.Kind]
+	if !ok {
+		return nil, ModelHeader{}, fmt.Errorf("%w: %q", ErrUnknownModel, saved.Header.Kind)
+	}
+	model := newModel()
+	if err := saved.decode(model); err != nil {
+		return nil, ModelHeader{}, err
+	}
+	return model, saved.Header, nil
+}
+
+// readModel reads a saved model and checks its format version.
+func readModel(r io.Reader) (*savedModel, error) {
+	saved := &savedModel{}
+	if err := json.NewDecoder(r).Decode(saved); err != nil {
+		return nil, err
+	}
+	switch saved.Header.Version {
+	case modelVersion:
+	default:
+		return nil, fmt.Errorf("%w: %d", ErrUnsupportedModel, saved.Header.Version)
+	}
+	return saved, nil
+}
+
+// decode decodes the saved model into the given pointer and checks it matches the header.
+func (saved *savedModel) decode(model PersistentModel) error {
+	if err := json.Unmarshal(saved.Model, model); err != nil {
+		return err
+	}
+	if vm, ok := model.(ValidatedModel); ok {
+		if err := vm.Validate(); err != nil {
+			return fmt.Errorf("invalid %s model: %w", saved.Header.Kind, err)
+		}
+	}
+	if n := model.Features(); n != saved.Header.Features {
+		return fmt.Errorf("model has %d features, header %d: %w", n, saved.Header.Features, ErrBadDim)
+	}
+	if pre := saved.Header.Preprocessing; pre != nil && (len(pre.Mean) != saved.Header.Features || len(pre.Scale) != saved.Header.Features) {
+		return fmt.Errorf("preprocessing for %d features, model has %d: %w", len(pre.Mean), saved.Header.Features, ErrBadDim)
+	}
+	return nil
+}
+
+// loadModel reads a model written by SaveModel into the given pointer.
+// Returns ErrUnknownModel if the saved kind does not match.
+func loadModel(r io.Reader, model PersistentModel) error {
+	saved, err := readModel(r)
+	if err != nil {
+		return err
+	}
+	kind, err := modelKind(model)
+	if err != nil {
+		return err
+	}
+	if kind != saved.Header.Kind {
+		return fmt.Errorf("%w: %q, expected %q", ErrUnknownModel, saved.Header.Kind, kind)
+	}
+	return saved.decode(model)
+}
+
+// Features implements PersistentModel.
+func (b LinearRegression) Features() int { return len(b.Θ) - 1 }
+
+// Save writes the model, see SaveModel.
+func (b LinearRegression) Save(w io.Writer) error { return SaveModel(w, &b, nil) }
+
+// Load reads a model written by Save.
+func (b *LinearRegression) Load(r io.Reader) error { return loadModel(r, b) }
+
+// Validate implements ValidatedModel, Θ needs to be a vector.
+func (b LinearRegression) Validate() error { return b.Θ.Validate() }
+
+// Features implements PersistentModel.
+func (b LogisticRegression) Features() int { return len(b.Θ) - 1 }
+
+// Save writes the model, see SaveModel.
+func (b LogisticRegression) Save(w io.Writer) error { return SaveModel(w, &b, nil) }
+
+// Load reads a model written by Save.
+func (b *LogisticRegression) Load(r io.Reader) error { return loadModel(r, b) }
+
+// Validate implements ValidatedModel, Θ needs to be a vector.
+func (b LogisticRegression) Validate() error { return b.Θ.Validate() }
+
+// Features implements PersistentModel.
+func (b SoftmaxRegression) Features() int { return len(b.Θ) - 1 }
+
+// Save writes the model, see SaveModel.
+func (b SoftmaxRegression) Save(w io.Writer) error { return SaveModel(w, &b, nil) }
+
+// Load reads a model written by Save.
+func (b *SoftmaxRegression) Load(r io.Reader) error { return loadModel(r, b) }
+
+// Validate implements ValidatedModel, Θ needs to be a non empty matrix.
+func (b SoftmaxRegression) Validate() error {
+	if err := b.Θ.Validate(); err != nil {
+		return err
+	}
+	if m, n := b.Θ.shape(); m == 0 || n == 0 {
+		return ErrBadDim
+	}
+	return nil
+}
+
+// Features implements PersistentModel, the input layer size.
+func (nn MLP) Features() int {
+	if len(nn.Layers) == 0 {
+		return 0
+	}
+	return len(nn.Layers[0].W) - 1
+}
+
+// Save writes the model, see SaveModel.
+func (nn MLP) Save(w io.Writer) error { return SaveModel(w, &nn, nil) }
+
+// Load reads a model written by Save.
+func (nn *MLP) Load(r io.Reader) error { return loadModel(r, nn) }
+
+// Validate implements ValidatedModel: each layer output feeds the next layer
+// input and softmax is only used by the output layer.
+func (nn MLP) Validate() error {
+	if len(nn.Layers) == 0 {
+		return ErrBadDim
+	}
+	for l, layer := range nn.Layers {
+		if err := layer.W.Validate(); err != nil {
+			return fmt.Errorf("layer %d: %w", l, err)
+		}
+		in, out := layer.W.shape()
+		if in < 2 || out == 0 {
+			return fmt.Errorf("layer %d: (%d,%d) weights: %w", l, in, out, ErrBadDim)
+		}
+		if l > 0 {
+			if _, prev := nn.Layers[l-1].W.shape(); in-1 != prev {
+				return fmt.Errorf("layer %d: %d inputs, previous layer has %d outputs: %w", l, in-1, prev, ErrBadDim)
+			}
+		}
+		if layer.Activation < ActivationIdentity || layer.Activation > ActivationSoftmax ||
+			(layer.Activation == ActivationSoftmax && l != len(nn.Layers)-1) {
+			return fmt.Errorf("layer %d: %w: activation %s", l, ErrInvalidModel, layer.Activation)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,167 @@
+package ml_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/creack/ml"
+)
+
+func TestSaveLoad(t *testing.T) {
+	nn := ml.NewMLP([]int{2, 3, 2}, []ml.Activation{ml.ActivationReLU, ml.ActivationSoftmax}, ml.NewRNG(1))
+	for i, elem := range []struct {
+		model    ml.PersistentModel
+		features int
+		params   func(ml.PersistentModel) ml.Vector
+	}{
+		{&ml.LinearRegression{Θ: ml.Vector{{1}, {2}}, Lambda: 0.5, L1Ratio: 0.1}, 1, func(m ml.PersistentModel) ml.Vector { return m.(*ml.LinearRegression).Θ }},
+		{&ml.LogisticRegression{Θ: ml.Vector{{-1}, {0.5}, {3}}}, 2, func(m ml.PersistentModel) ml.Vector { return m.(*ml.LogisticRegression).Θ }},
+		{&ml.SoftmaxRegression{Θ: ml.Matrix{{1, 2, 3}, {4, 5, 6}}}, 1, func(m ml.PersistentModel) ml.Vector { return m.(*ml.SoftmaxRegression).Parameters() }},
+		{nn, 2, func(m ml.PersistentModel) ml.Vector { return m.(*ml.MLP).Parameters() }},
+	} {
+		buf := bytes.NewBuffer(nil)
+		if err := ml.SaveModel(buf, elem.model, nil); err != nil {
+			t.Fatalf("[%d] Error saving the model: %s", i, err)
+		}
+		model, header, err := ml.LoadModel(buf)
+		if err != nil {
+			t.Fatalf("[%d] Error loading the model: %s", i, err)
+		}
+		if header.Features != elem.features || header.Version != 1 || header.Preprocessing != nil {
+			t.Errorf("[%d] Unexpected header: %+v", i, header)
+		}
+		if expect, got := elem.params(elem.model), elem.params(model); !ml.Matrix(got).Equal(ml.Matrix(expect)) {
+			t.Errorf("[%d] Unexpected loaded parameters.\nExpect:\t%v\nGot:\t%v", i, expect, got)
+		}
+	}
+
+	// Regularization and activations are part of the model.
+	buf := bytes.NewBuffer(nil)
+	if err := (ml.LinearRegression{Θ: ml.Vector{{1}, {2}}, Lambda: 0.5, L1Ratio: 0.1}).Save(buf); err != nil {
+		t.Fatalf("Error saving the model: %s", err)
+	}
+	var lr ml.LinearRegression
+	if err := lr.Load(buf); err != nil {
+		t.Fatalf("Error loading the model: %s", err)
+	}
+	if lr.Lambda != 0.5 || lr.L1Ratio != 0.1 {
+		t.Fatalf("Unexpected loaded regularization: %v, %v", lr.Lambda, lr.L1Ratio)
+	}
+	buf.Reset()
+	if err := nn.Save(buf); err != nil {
+		t.Fatalf("Error saving the network: %s", err)
+	}
+	var nn2 ml.MLP
+	if err := nn2.Load(buf); err != nil {
+		t.Fatalf("Error loading the network: %s", err)
+	}
+	x := ml.Matrix{{0.5, -1}, {2, 3}}
+	if expect, got := nn.Forward(x), nn2.Forward(x); !got.Equal(expect) {
+		t.Fatalf("Unexpected loaded network output.\nExpect:\n%s\nGot:\n%s", expect, got)
+	}
+}
+
+func TestSavePreprocessing(t *testing.T) {
+	ds := ml.Dataset{X: ml.Matrix{{1000, 1}, {2000, 1}, {3000, 1}, {4000, 1}}, Y: ml.Vector{{0}, {1}, {0}, {1}}}
+	pre := ml.Standardize(ds.X)
+	if expect := []float64{2500, 1}; pre.Mean[0] != expect[0] || pre.Mean[1] != expect[1] || pre.Scale[1] != 1 {
+		t.Fatalf("Unexpected preprocessing: %+v", pre)
+	}
+	lr := &ml.LogisticRegression{Θ: ml.NewVector(3)}
+	if _, err := lr.GradientDescent(context.Background(), pre.Apply(ds), 1, ml.GradientDescentOptions()); err != nil {
+		t.Fatalf("Error training the model: %s", err)
+	}
+
+	buf := bytes.NewBuffer(nil)
+	if err := ml.SaveModel(buf, lr, pre); err != nil {
+		t.Fatalf("Error saving the model: %s", err)
+	}
+	model, header, err := ml.LoadModel(buf)
+	if err != nil {
+		t.Fatalf("Error loading the model: %s", err)
+	}
+	lr2, ok := model.(*ml.LogisticRegression)
+	if !ok || header.Kind != "logistic_regression" || header.Preprocessing == nil {
+		t.Fatalf("Unexpected loaded model %T, header %+v", model, header)
+	}
+	for i, elem := range []struct {
+		x      float64
+		expect float64
+	}{
+		{1000, 0},
+		{5000, 1},
+	} {
+		if got := lr2.Predict(header.Preprocessing.ApplyVector(ml.Vector{{elem.x}, {1}}), 0.5); got != elem.expect {
+			t.Errorf("[%d] Unexpected prediction for %g: %g", i, elem.x, got)
+		}
+	}
+
+	if err := ml.SaveModel(buf, lr, &ml.Preprocessing{Mean: []float64{1}, Scale: []float64{1}}); !errors.Is(err, ml.ErrBadDim) {
+		t.Fatalf("Unexpected error for a preprocessing mismatch: %v", err)
+	}
+}
+
+func TestLoadModelErrors(t *testing.T) {
+	for i, elem := range []struct {
+		data   string
+		expect error
+	}{
+		{`{"header":{"kind":"linear_regression","version":99}}`, ml.ErrUnsupportedModel},
+		{`{"header":{"kind":"linear_regression","features":1},"model":{"Θ":{"rows":2,"cols":1,"data":[1,2]}}}`, ml.ErrUnsupportedModel},
+		{`{"header":{"kind":"unknown","version":1}}`, ml.ErrUnknownModel},
+		{`{"header":{"kind":"linear_regression","version":1,"features":3},"model":{"Θ":{"rows":2,"cols":1,"data":[1,2]}}}`, ml.ErrBadDim},
+	} {
+		if _, _, err := ml.LoadModel(strings.NewReader(elem.data)); !errors.Is(err, elem.expect) {
+			t.Errorf("[%d] Unexpected error: %v", i, err)
+		}
+	}
+
+	// Models are validated after decoding.
+	for i, elem := range []struct {
+		kind   string
+		model  string
+		expect error
+	}{
+		{"linear_regression", `{}`, ml.ErrUninitialized},
+		{"logistic_regression", `{"Θ":{"rows":2,"cols":2,"data":[1,2,3,4]}}`, ml.ErrNotAVector},
+		{"softmax_regression", `{"Θ":{"rows":0,"cols":0,"data":[]}}`, ml.ErrBadDim},
+		{"mlp", `{"Layers":[]}`, ml.ErrBadDim},
+		{"mlp", `{"Layers":[{"W":{"rows":2,"cols":3,"data":[0,0,0,0,0,0]},"Activation":2},{"W":{"rows":3,"cols":1,"data":[0,0,0]},"Activation":0}]}`, ml.ErrBadDim},
+		{"mlp", `{"Layers":[{"W":{"rows":2,"cols":1,"data":[0,0]},"Activation":42}]}`, ml.ErrInvalidModel},
+		{"mlp", `{"Layers":[{"W":{"rows":2,"cols":2,"data":[0,0,0,0]},"Activation":4},{"W":{"rows":3,"cols":1,"data":[0,0,0]},"Activation":0}]}`, ml.ErrInvalidModel},
+	} {
+		data := `{"header":{"kind":"` + elem.kind + `","version":1,"features":1},"model":` + elem.model + `}`
+		if _, _, err := ml.LoadModel(strings.NewReader(data)); !errors.Is(err, elem.expect) {
+			t.Errorf("[%d] Unexpected error loading an invalid %s: %v", i, elem.kind, err)
+		}
+	}
+
+	// Models are validated before saving.
+	for i, elem := range []struct {
+		model  ml.PersistentModel
+		expect error
+	}{
+		{ml.LinearRegression{}, ml.ErrUninitialized},
+		{&ml.MLP{Layers: []ml.Layer{
+			{W: ml.NewMatrix(2, 2), Activation: ml.ActivationSoftmax},
+			{W: ml.NewMatrix(3, 1), Activation: ml.ActivationIdentity},
+		}}, ml.ErrInvalidModel},
+	} {
+		if err := ml.SaveModel(bytes.NewBuffer(nil), elem.model, nil); !errors.Is(err, elem.expect) {
+			t.Errorf("[%d] Unexpected error saving an invalid %T: %v", i, elem.model, err)
+		}
+	}
+
+	// Load requires the saved kind.
+	buf := bytes.NewBuffer(nil)
+	if err := (ml.LinearRegression{Θ: ml.NewVector(2)}).Save(buf); err != nil {
+		t.Fatalf("Error saving the model: %s", err)
+	}
+	var lr ml.LogisticRegression
+	if err := lr.Load(buf); !errors.Is(err, ml.ErrUnknownModel) {
+		t.Fatalf("Unexpected error for a kind mismatch: %v", err)
+	}
+}
@@ -0,0 +1,308 @@
+package ml
+
+import (
+	"context"
+	"math"
+)
+
+// QuasiNewtonOptions configures BFGS and LBFGS.
+type QuasiNewtonOptions struct {
+	MaxIterations     int     // Defaults to 1000.
+	GradientTolerance float64 // Max absolute gradient component at the minimum, defaults to 1e-8.
+	Memory            int     // Number of corrections kept by L-BFGS, defaults to 10.
+
+	Observers    []Observer // Notified of the training progress.
+	ObserveEvery int        // Notifies the observers every given number of iterations, defaults to 1.
+
+	// Early stopping on a held-out dataset, same as TrainOptions.
+	// The validation cost is evaluated with the cost function.
+	Validation    Dataset
+	ValidateEvery int
+	Patience      int
+}
+
+// Wolfe line search parameters: sufficient decrease and curvature.
+const (
+	wolfeC1 = 1e-4
+	wolfeC2 = 0.9
+)
+
+// quasiNewtonDirection approximates the inverse Hessian.
+type quasiNewtonDirection interface {
+	direction(grad Vector) Vector // Returns -H * grad.
+	update(s, y Vector)           // Adds the θ change s and gradient change y.
+}
+
+// BFGS minimizes the cost with the Broyden–Fletcher–Goldfarb–Shanno method
+// and a Wolfe line search. The dense (n,n) inverse Hessian is kept, see LBFGS
+// for many parameters.
+// cost and gradient are evaluated against the parameters θ, which are updated in place,
+// i.e. BFGS(ctx, lr.Θ, lr.SquaredError, lr.Gradient, dataset, opts).
+// With a validation dataset, θ is set back to the parameters with the lowest
+// validation cost at the end, see QuasiNewtonOptions.Validation.
+// Stops with ctx.Err() when the context is done, θ holding the last accepted step
+// or the best validated one.
+// Aborts with a *NonFiniteError when the cost or the gradient is not finite.
+func BFGS(ctx context.Context, θ Vector, cost func(Dataset) float64, gradient func(Dataset) Vector, dataset Dataset, opts QuasiNewtonOptions) (TrainResult, error) {
+	return quasiNewton(ctx, θ, cost, gradient, dataset, opts, &bfgs{})
+}
+
+// LBFGS minimizes the cost with the limited-memory BFGS method and a Wolfe
+// line search. Only the last opts.Memory corrections are kept.
+// Same usage as BFGS.
+func LBFGS(ctx context.Context, θ Vector, cost func(Dataset) float64, gradient func(Dataset) Vector, dataset Dataset, opts QuasiNewtonOptions) (TrainResult, error) {
+	memory := opts.Memory
+	if memory <= 0 {
+		memory = 10
+	}
+	return quasiNewton(ctx, θ, cost, gradient, dataset, opts, &lbfgs{memory: memory})
+}
+
+func quasiNewton(ctx context.Context, θ Vector, cost func(Dataset) float64, gradient func(Dataset) Vector, dataset Dataset, opts QuasiNewtonOptions, dir quasiNewtonDirection) (ret TrainResult, err error) {
+	defer recoverNonFinite(&err)
+
+	maxIter := opts.MaxIterations
+	if maxIter <= 0 {
+		maxIter = 1000
+	}
+	tol := defaultFloat(opts.GradientTolerance, 1e-8)
+
+	n := newNotifier(opts.Observers, opts.ObserveEvery)
+	ls := &lineSearch{θ: θ, cost: cost, gradient: gradient, dataset: dataset}
+	model := funcModel{θ: θ, cost: cost, gradient: gradient}
+	v := newValidator(opts.Validation, opts.ValidateEvery, opts.Patience)
+	f, g := ls.evaluate()
+	ret.Cost = f
+	ret.History = append(ret.History, f)
+	v.validate(model, &ret)
+	defer v.restore(model, &ret)
+
+	ret.Reason = TerminationMaxIterations
+	for ret.Iterations < maxIter {
+		if ctx.Err() != nil {
+			ret.Evaluations, ret.Cost, ret.Reason = ls.evaluations, f, TerminationCanceled
+			return ret, ctx.Err()
+		}
+		if Matrix(g).normInf() <= tol {
+			ret.Reason = TerminationGradientTolerance
+			break
+		}
+		p := dir.direction(g)
+		if dot(p, g) >= 0 {
+			// Not a descent direction, restart from the steepest descent.
+			p = Vector(Matrix(g).Scale(-1))
+		}
+		x0 := Matrix(θ).Copy()
+		f1, g1, ok := ls.search(Vector(x0), p, f, dot(g, p))
+		if !ok {
+			for j := range θ {
+				θ[j][0] = x0[j][0]
+			}
+			ret.Reason = TerminationLineSearchFailed
+			break
+		}
+		s, y := NewVector(len(θ)), NewVector(len(θ))
+		for j := range θ {
+			s[j][0] = θ[j][0] - x0[j][0]
+			y[j][0] = g1[j][0] - g[j][0]
+		}
+		dir.update(s, y)
+		f, g = f1, g1
+		ret.Iterations++
+		ret.Cost = f
+		ret.History = append(ret.History, f)
+		vc, stop := v.validate(model, &ret)
+		n.notify(Event{Iteration: ret.Iterations, Cost: f, Θ: θ, GradientNorm: Matrix(g).normInf(), ValidationCost: vc})
+		if stop {
+			ret.Reason = TerminationEarlyStopping
+			break
+		}
+	}
+	ret.Evaluations = ls.evaluations
+	ret.Cost = f
+	return ret, nil
+}
+
+// funcModel is the Model of the cost and gradient functions of the quasi-Newton methods.
+type funcModel struct {
+	θ        Vector
+	cost     func(Dataset) float64
+	gradient func(Dataset) Vector
+}
+
+// Parameters implements Model.
+func (fm funcModel) Parameters() Vector { return fm.θ }
+
+// Cost implements Model.
+func (fm funcModel) Cost(dataset Dataset) float64 { return fm.cost(dataset) }
+
+// Gradient implements Model.
+func (fm funcModel) Gradient(dataset Dataset) Vector { return fm.gradient(dataset) }
+
+// lineSearch evaluates the cost along a direction.
+type lineSearch struct {
+	θ           Vector
+	cost        func(Dataset) float64
+	gradient    func(Dataset) Vector
+	dataset     Dataset
+	evaluations int
+}
+
+// evaluate returns the cost and gradient at the current parameters.
+func (ls *lineSearch) evaluate() (float64, Vector) {
+	ls.evaluations++
+	f := ls.cost(ls.dataset)
+	g := ls.gradient(ls.dataset)
+	if !isFinite(f) {
+		panic(&NonFiniteError{Op: "LineSearch", Row: -1, Col: -1, Value: f})
+	}
+	for j, elem := range g {
+		if !isFinite(elem[0]) {
+			panic(&NonFiniteError{Op: "LineSearch", Row: j, Col: 0, Value: elem[0]})
+		}
+	}
+	return f, g
+}
+
+// at evaluates φ(α) = cost(x0 + α * p) and φ'(α).
+func (ls *lineSearch) at(x0, p Vector, α float64) (float64, float64, Vector) {
+	for j := range ls.θ {
+		ls.θ[j][0] = x0[j][0] + α*p[j][0]
+	}
+	f, g := ls.evaluate()
+	return f, dot(g, p), g
+}
+
+// search finds a step satisfying the strong Wolfe conditions (Nocedal & Wright, algorithm 3.5),
+// starting from α = 1. On success, θ is left at the accepted step.
+func (ls *lineSearch) search(x0, p Vector, f0, df0 float64) (float64, Vector, bool) {
+	const maxSteps = 30
+	prevα, prevF, prevDF := 0., f0, df0
+	α := 1.
+	for i := 0; i < maxSteps; i++ {
+		f, df, g := ls.at(x0, p, α)
+		if f > f0+wolfeC1*α*df0 || (i > 0 && f >= prevF) {
+			return ls.zoom(x0, p, f0, df0, prevα, prevF, prevDF, α, f)
+		}
+		if math.Abs(df) <= -wolfeC2*df0 {
+			return f, g, true
+		}
+		if df >= 0 {
+			return ls.zoom(x0, p, f0, df0, α, f, df, prevα, prevF)
+		}
+		prevα, prevF, prevDF = α, f, df
+		α *= 2
+	}
+	return 0, nil, false
+}
+
+// zoom narrows the [lo,hi] bracket until a step satisfies the strong Wolfe conditions
+// (Nocedal & Wright, algorithm 3.6). lo holds the lowest cost found so far.
+func (ls *lineSearch) zoom(x0, p Vector, f0, df0, lo, fLo, dfLo, hi, fHi float64) (float64, Vector, bool) {
+	const maxSteps = 50
+	for i := 0; i < maxSteps; i++ {
+		// Minimum of the quadratic interpolating φ(lo), φ'(lo) and φ(hi), safeguarded
+		// to stay inside the bracket.
+		d := hi - lo
+		α := lo - dfLo*d*d/(2*(fHi-fLo-dfLo*d))
+		if math.IsNaN(α) || math.Abs(α-lo) < 0.1*math.Abs(d) || math.Abs(hi-α) < 0.1*math.Abs(d) {
+			α = lo + d/2
+		}
+		f, df, g := ls.at(x0, p, α)
+		if f > f0+wolfeC1*α*df0 || f >= fLo {
+			hi, fHi = α, f
+			continue
+		}
+		if math.Abs(df) <= -wolfeC2*df0 {
+			return f, g, true
+		}
+		if df*(hi-lo) >= 0 {
+			hi, fHi = lo, fLo
+		}
+		lo, fLo, dfLo = α, f, df
+	}
+	return 0, nil, false
+}
+
+// bfgs keeps the dense inverse Hessian approximation.
+type bfgs struct {
+	h Matrix
+}
+
+func (b *bfgs) direction(grad Vector) Vector {
+	if b.h == nil {
+		return Vector(Matrix(grad).Scale(-1))
+	}
+	return Vector(b.h.Mul(Matrix(grad)).Scale(-1))
+}
+
+// update applies H = (I - ρ s y^T) H (I - ρ y s^T) + ρ s s^T, ρ = 1 / y^T s.
+// Skipped when the curvature condition y^T s > 0 does not hold.
+func (b *bfgs) update(s, y Vector) {
+	sy := dot(s, y)
+	if sy <= 1e-12*math.Sqrt(dot(s, s)*dot(y, y)) {
+		return
+	}
+	n := len(s)
+	if b.h == nil {
+		// Scale the initial approximation (Nocedal & Wright, equation 6.20).
+		b.h = NewMatrix(n, n).Identity().Scale(sy / dot(y, y))
+	}
+	ρ := 1 / sy
+	hy := b.h.Mul(Matrix(y))
+	yhy := dot(y, Vector(hy))
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			b.h[i][j] += -ρ*(hy[i][0]*s[j][0]+s[i][0]*hy[j][0]) + (ρ*ρ*yhy+ρ)*s[i][0]*s[j][0]
+		}
+	}
+}
+
+// lbfgs keeps the last corrections.
+type lbfgs struct {
+	memory int
+	s, y   []Vector
+}
+
+// direction uses the two-loop recursion (Nocedal & Wright, algorithm 7.4).
+func (l *lbfgs) direction(grad Vector) Vector {
+	q := Vector(Matrix(grad).Copy())
+	k := len(l.s)
+	αs := make([]float64, k)
+	for i := k - 1; i >= 0; i-- {
+		αs[i] = dot(l.s[i], q) / dot(l.y[i], l.s[i])
+		for j := range q {
+			q[j][0] -= αs[i] * l.y[i][j][0]
+		}
+	}
+	if k > 0 {
+		γ := dot(l.s[k-1], l.y[k-1]) / dot(l.y[k-1], l.y[k-1])
+		q = Vector(Matrix(q).Scale(γ))
+	}
+	for i := 0; i < k; i++ {
+		β := dot(l.y[i], q) / dot(l.y[i], l.s[i])
+		for j := range q {
+			q[j][0] += l.s[i][j][0] * (αs[i] - β)
+		}
+	}
+	return Vector(Matrix(q).Scale(-1))
+}
+
+func (l *lbfgs) update(s, y Vector) {
+	if dot(s, y) <= 1e-12*math.Sqrt(dot(s, s)*dot(y, y)) {
+		return
+	}
+	if len(l.s) == l.memory {
+		l.s, l.y = l.s[1:], l.y[1:]
+	}
+	l.s, l.y = append(l.s, s), append(l.y, y)
+}
+
+// dot returns the dot product of the given vectors.
+func dot(v1, v2 Vector) float64 {
+	var sum Accumulator
+	for j := range v1 {
+		sum.Add(v1[j][0] * v2[j][0])
+	}
+	return sum.Sum()
+}
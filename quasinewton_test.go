@@ -0,0 +1,120 @@
+package ml_test
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/creack/ml"
+)
+
+// rosenbrock returns the cost and gradient functions of the Rosenbrock function
+// f(x, y) = (1-x)^2 + 100(y-x^2)^2 evaluated at θ, minimum at (1, 1).
+func rosenbrock(θ ml.Vector) (func(ml.Dataset) float64, func(ml.Dataset) ml.Vector) {
+	cost := func(ml.Dataset) float64 {
+		x, y := θ[0][0], θ[1][0]
+		return (1-x)*(1-x) + 100*(y-x*x)*(y-x*x)
+	}
+	gradient := func(ml.Dataset) ml.Vector {
+		x, y := θ[0][0], θ[1][0]
+		return ml.Vector{{-2*(1-x) - 400*x*(y-x*x)}, {200 * (y - x*x)}}
+	}
+	return cost, gradient
+}
+
+func TestQuasiNewtonLinearRegression(t *testing.T) {
+	optimum := &ml.LinearRegression{Θ: ml.NewVector(3)}
+	if err := optimum.NormalEquation(testRegularizationDataset); err != nil {
+		t.Fatalf("Error solving the normal equation: %s", err)
+	}
+
+	for name, minimize := range map[string]func(context.Context, ml.Vector, func(ml.Dataset) float64, func(ml.Dataset) ml.Vector, ml.Dataset, ml.QuasiNewtonOptions) (ml.TrainResult, error){
+		"bfgs":  ml.BFGS,
+		"lbfgs": ml.LBFGS,
+	} {
+		lr := &ml.LinearRegression{Θ: ml.NewVector(3)}
+		res, err := minimize(context.Background(), lr.Θ, lr.SquaredError, lr.Gradient, testRegularizationDataset, ml.QuasiNewtonOptions{})
+		if err != nil {
+			t.Fatalf("%s: error minimizing: %s", name, err)
+		}
+		if res.Reason != ml.TerminationGradientTolerance {
+			t.Fatalf("%s: unexpected termination: %s", name, res.Reason)
+		}
+		if res.Iterations > 20 || res.Evaluations < res.Iterations {
+			t.Fatalf("%s: unexpected iteration count: %+v", name, res)
+		}
+		if !approxEqual(ml.Matrix(lr.Θ), ml.Matrix(optimum.Θ), 1e-6) {
+			t.Fatalf("%s: unexpected Θ.\nExpect:\t%v\nGot:\t%v", name, optimum.Θ, lr.Θ)
+		}
+		if expect, got := stringify(lr.SquaredError(testRegularizationDataset)), stringify(res.Cost); expect != got {
+			t.Fatalf("%s: unexpected reported cost.\nExpect:\t%s\nGot:\t%s", name, expect, got)
+		}
+	}
+}
+
+func TestQuasiNewtonLogisticRegression(t *testing.T) {
+	lr := &ml.LogisticRegression{Θ: ml.NewVector(2)}
+	res, err := ml.LBFGS(context.Background(), lr.Θ, lr.Cost, lr.Gradient, testLogisticDataset, ml.QuasiNewtonOptions{Memory: 3})
+	if err != nil {
+		t.Fatalf("Error minimizing: %s", err)
+	}
+	if res.Reason != ml.TerminationGradientTolerance {
+		t.Fatalf("Unexpected termination: %s (%+v)", res.Reason, res)
+	}
+	for j := range lr.Θ {
+		if d := lr.PartialDerivative(testLogisticDataset, j); math.Abs(d) > 1e-8 {
+			t.Fatalf("Non zero partial derivative for Θ[%d]: %g", j, d)
+		}
+	}
+}
+
+func TestQuasiNewtonRosenbrock(t *testing.T) {
+	for name, minimize := range map[string]func(context.Context, ml.Vector, func(ml.Dataset) float64, func(ml.Dataset) ml.Vector, ml.Dataset, ml.QuasiNewtonOptions) (ml.TrainResult, error){
+		"bfgs":  ml.BFGS,
+		"lbfgs": ml.LBFGS,
+	} {
+		θ := ml.Vector{{-1.2}, {1}}
+		cost, gradient := rosenbrock(θ)
+		res, err := minimize(context.Background(), θ, cost, gradient, ml.Dataset{}, ml.QuasiNewtonOptions{})
+		if err != nil {
+			t.Fatalf("%s: error minimizing: %s", name, err)
+		}
+		if res.Reason != ml.TerminationGradientTolerance || res.Iterations > 100 {
+			t.Fatalf("%s: unexpected result: %+v", name, res)
+		}
+		if !approxEqual(ml.Matrix(θ), ml.Matrix{{1}, {1}}, 1e-6) {
+			t.Fatalf("%s: unexpected minimum: %v", name, θ)
+		}
+	}
+
+	// Iteration limit.
+	θ := ml.Vector{{-1.2}, {1}}
+	cost, gradient := rosenbrock(θ)
+	res, err := ml.BFGS(context.Background(), θ, cost, gradient, ml.Dataset{}, ml.QuasiNewtonOptions{MaxIterations: 3})
+	if err != nil {
+		t.Fatalf("Error minimizing: %s", err)
+	}
+	if res.Reason != ml.TerminationMaxIterations || res.Iterations != 3 {
+		t.Fatalf("Unexpected result: %+v", res)
+	}
+}
+
+func TestQuasiNewtonLineSearchFailed(t *testing.T) {
+	θ := ml.Vector{{-1.2}, {1}}
+	cost, gradient := rosenbrock(θ)
+	// Bogus gradient pointing uphill.
+	bogus := func(ds ml.Dataset) ml.Vector {
+		return ml.Vector(ml.Matrix(gradient(ds)).Scale(-1))
+	}
+	res, err := ml.LBFGS(context.Background(), θ, cost, bogus, ml.Dataset{}, ml.QuasiNewtonOptions{})
+	if err != nil {
+		t.Fatalf("Error minimizing: %s", err)
+	}
+	if res.Reason != ml.TerminationLineSearchFailed {
+		t.Fatalf("Unexpected termination: %s", res.Reason)
+	}
+	// θ is restored to the last accepted point.
+	if θ[0][0] != -1.2 || θ[1][0] != 1 {
+		t.Fatalf("θ not restored: %v", θ)
+	}
+}
@@ -0,0 +1,133 @@
+package ml
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// ErrNoClosedForm is returned when solving a L1 regularized regression in closed form.
+var ErrNoClosedForm = errors.New("L1 regularization has no closed form solution")
+
+// penalty returns the elastic net regularization term, excluding the bias Θ[0]:
+// $$\lambda (r\sum_{j=1}^{n} |\theta_j| + \frac{1-r}{2}\sum_{j=1}^{n} \theta_j^2)$$
+// with r = L1Ratio. The cost adds penalty / m.
+func (b LinearRegression) penalty() float64 {
+	if b.Lambda == 0 {
+		return 0
+	}
+	var l1, l2 Accumulator
+	for _, line := range b.Θ[1:] {
+		l1.Add(math.Abs(line[0]))
+		l2.Add(line[0] * line[0])
+	}
+	return b.Lambda * (b.L1Ratio*l1.Sum() + (1-b.L1Ratio)/2*l2.Sum())
+}
+
+// penaltyDerivative returns the derivative of the penalty for Θ[j].
+// The L1 subgradient at 0 is 0.
+func (b LinearRegression) penaltyDerivative(j int) float64 {
+	if b.Lambda == 0 || j == 0 {
+		return 0
+	}
+	θ := b.Θ[j][0]
+	sign := 0.
+	if θ > 0 {
+		sign = 1
+	} else if θ < 0 {
+		sign = -1
+	}
+	return b.Lambda * (b.L1Ratio*sign + (1-b.L1Ratio)*θ)
+}
+
+// NormalEquation sets Θ to the closed form solution of the (ridge) least squares:
+// $$\Theta = (X^TX + \lambda L)^{-1} X^Ty$$
+// where L is the identity without the bias term.
+// Returns ErrNoClosedForm when L1Ratio is set.
+// panic with ErrSingularMatrix if X^TX + λL is not invertible.
+func (b *LinearRegression) NormalEquation(dataset Dataset) error {
+	if b.Lambda != 0 && b.L1Ratio != 0 {
+		return ErrNoClosedForm
+	}
+	dataset = dataset.withBias(len(b.Θ))
+	xt := dataset.X.T()
+	a := xt.Mul(dataset.X.View())
+	for j := 1; j < len(a); j++ {
+		a[j][j] += b.Lambda
+	}
+	θ := a.Inverse().Mul(xt.Mul(Matrix(dataset.Y).View()))
+	for j := range b.Θ {
+		b.Θ[j][0] = θ[j][0]
+	}
+	return nil
+}
+
+// softThreshold is the lasso shrinkage operator: sign(x) * max(|x|-t, 0).
+func softThreshold(x, t float64) float64 {
+	switch {
+	case x > t:
+		return x - t
+	case x < -t:
+		return x + t
+	}
+	return 0
+}
+
+// CoordinateDescent minimizes the regularized squared error one Θ component at a time.
+// Each update is exact, including the L1 soft thresholding, which makes it the method
+// of choice for lasso and elastic net. Stops when no component moves more than tol.
+// Returns ErrNoConvergence if not converged after maxIter sweeps and ctx.Err()
+// when the context is done, each sweep only lowering the cost.
+func (b *LinearRegression) CoordinateDescent(ctx context.Context, dataset Dataset, maxIter int, tol float64) error {
+	dataset = dataset.withBias(len(b.Θ))
+	m, n := dataset.X.Dim()
+
+	// Column squared norms and residuals r = y - X*Θ.
+	z := make([]float64, n)
+	r := make([]float64, m)
+	for i, row := range dataset.X {
+		for j, x := range row {
+			z[j] += x * x
+		}
+		r[i] = dataset.Y[i][0] - b.predict(row)
+	}
+
+	l1, l2 := b.Lambda*b.L1Ratio, b.Lambda*(1-b.L1Ratio)
+	for iter := 0; iter < maxIter; iter++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		step := 0.
+		for j := 0; j < n; j++ {
+			if z[j] == 0 {
+				continue
+			}
+			old := b.Θ[j][0]
+			// ρ = Sum of x_ij * (r_i + Θ_j * x_ij), the correlation with the partial residual.
+			var rho Accumulator
+			for i, row := range dataset.X {
+				rho.Add(row[j] * (r[i] + old*row[j]))
+			}
+			θ := rho.Sum() / z[j]
+			if j > 0 {
+				θ = softThreshold(rho.Sum(), l1) / (z[j] + l2)
+			}
+			if θ == old {
+				continue
+			}
+			for i, row := range dataset.X {
+				r[i] -= (θ - old) * row[j]
+			}
+			b.Θ[j][0] = θ
+			step = math.Max(step, math.Abs(θ-old))
+		}
+		if !b.Θ.IsFinite() {
+			return fmt.Errorf("iteration %d: %w", iter, &NonFiniteError{Op: "CoordinateDescent", Row: -1, Col: -1, Value: step})
+		}
+		if step <= tol {
+			return nil
+		}
+	}
+	return ErrNoConvergence
+}
@@ -0,0 +1,105 @@
+package ml_test
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/creack/ml"
+)
+
+var testRegularizationDataset = ml.Dataset{
+	X: ml.Matrix{
+		{1, 0.5},
+		{2, -1},
+		{3, 0.25},
+		{4, 2},
+		{5, -0.5},
+	},
+	Y: ml.Vector{
+		{2.1},
+		{3.9},
+		{6.2},
+		{7.8},
+		{10.1},
+	},
+}
+
+func TestRidge(t *testing.T) {
+	closed := &ml.LinearRegression{Θ: ml.NewVector(3), Lambda: 2}
+	if err := closed.NormalEquation(testRegularizationDataset); err != nil {
+		t.Fatalf("Error solving the normal equation: %s", err)
+	}
+	// The closed form solution is the minimum of the regularized cost.
+	for j := range closed.Θ {
+		if d := closed.PartialDerivative(testRegularizationDataset, j); math.Abs(d) > 1e-9 {
+			t.Fatalf("Non zero partial derivative for Θ[%d] at the ridge solution: %g", j, d)
+		}
+	}
+	cd := &ml.LinearRegression{Θ: ml.NewVector(3), Lambda: 2}
+	if err := cd.CoordinateDescent(context.Background(), testRegularizationDataset, 10000, 1e-12); err != nil {
+		t.Fatalf("Error running coordinate descent: %s", err)
+	}
+	if !approxEqual(ml.Matrix(cd.Θ), ml.Matrix(closed.Θ), 1e-9) {
+		t.Fatalf("Coordinate descent and normal equation differ\n%s\n%s\n", cd.Θ, closed.Θ)
+	}
+
+	// The penalty shrinks the weights but not the bias.
+	plain := &ml.LinearRegression{Θ: ml.NewVector(3)}
+	if err := plain.NormalEquation(testRegularizationDataset); err != nil {
+		t.Fatalf("Error solving the normal equation: %s", err)
+	}
+	strong := &ml.LinearRegression{Θ: ml.NewVector(3), Lambda: 1e12}
+	if err := strong.NormalEquation(testRegularizationDataset); err != nil {
+		t.Fatalf("Error solving the normal equation: %s", err)
+	}
+	if math.Abs(closed.Θ[1][0]) >= math.Abs(plain.Θ[1][0]) {
+		t.Fatalf("Ridge did not shrink the weight: %g >= %g", closed.Θ[1][0], plain.Θ[1][0])
+	}
+	if expect, got := stringify(6.02), stringify(strong.Θ[0][0]); expect != got {
+		t.Fatalf("Unexpected bias for strong ridge, expected the mean of y.\nExpect:\t%s\nGot:\t%s", expect, got)
+	}
+}
+
+func TestLasso(t *testing.T) {
+	lr := &ml.LinearRegression{Θ: ml.NewVector(3), Lambda: 3, L1Ratio: 1}
+	if err := lr.NormalEquation(testRegularizationDataset); err != ml.ErrNoClosedForm {
+		t.Fatalf("Unexpected error for lasso normal equation: %v", err)
+	}
+	if err := lr.CoordinateDescent(context.Background(), testRegularizationDataset, 10000, 1e-12); err != nil {
+		t.Fatalf("Error running coordinate descent: %s", err)
+	}
+	// The noise feature is dropped, the real one is kept.
+	if lr.Θ[2][0] != 0 {
+		t.Fatalf("Lasso did not zero the noise feature: %s", lr.Θ)
+	}
+	if lr.Θ[1][0] == 0 {
+		t.Fatalf("Lasso zeroed the main feature: %s", lr.Θ)
+	}
+	// The solution is a minimum: moving any component increases the cost.
+	cost := lr.SquaredError(testRegularizationDataset)
+	for j := range lr.Θ {
+		for _, eps := range []float64{-1e-4, 1e-4} {
+			lr.Θ[j][0] += eps
+			if c := lr.SquaredError(testRegularizationDataset); c < cost {
+				t.Fatalf("Cost decreased moving Θ[%d] by %g: %g < %g", j, eps, c, cost)
+			}
+			lr.Θ[j][0] -= eps
+		}
+	}
+}
+
+func TestElasticNetGradientDescent(t *testing.T) {
+	// A small penalty keeps all the weights non zero, where the L1 term is differentiable.
+	cd := &ml.LinearRegression{Θ: ml.NewVector(3), Lambda: 0.05, L1Ratio: 0.5}
+	if err := cd.CoordinateDescent(context.Background(), testRegularizationDataset, 10000, 1e-13); err != nil {
+		t.Fatalf("Error running coordinate descent: %s", err)
+	}
+	gd := &ml.LinearRegression{Θ: ml.NewVector(3), Lambda: 0.05, L1Ratio: 0.5}
+	if _, err := gd.GradientDescent(context.Background(), testRegularizationDataset, 0.02, ml.GradientDescentOptions()); err != nil {
+		t.Fatalf("Error running gradient descent: %s", err)
+	}
+	if !approxEqual(ml.Matrix(cd.Θ), ml.Matrix(gd.Θ), 1e-6) {
+		t.Fatalf("Coordinate descent and gradient descent differ\n%s\n%s\n", cd.Θ, gd.Θ)
+	}
+}
@@ -0,0 +1,64 @@
+package ml
+
+import "math/bits"
+
+// RNG is a small seeded pseudo-random generator (SplitMix64).
+// Its whole state is the exported State field, so it can be saved with the
+// model and restored to reproduce the exact same sequence.
+// Implements rand.Source64, use rand.New(rng) for the other distributions.
+type RNG struct {
+	State uint64
+}
+
+// NewRNG instantiates a new generator with the given seed.
+func NewRNG(seed uint64) *RNG {
+	return &RNG{State: seed}
+}
+
+// Uint64 returns a pseudo-random 64-bit value.
+func (r *RNG) Uint64() uint64 {
+	r.State += 0x9e3779b97f4a7c15
+	z := r.State
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return z ^ (z >> 31)
+}
+
+// Int63 implements rand.Source.
+func (r *RNG) Int63() int64 {
+	return int64(r.Uint64() >> 1)
+}
+
+// Seed implements rand.Source.
+func (r *RNG) Seed(seed int64) {
+	r.State = uint64(seed)
+}
+
+// Float64 returns a pseudo-random number in [0,1).
+func (r *RNG) Float64() float64 {
+	return float64(r.Uint64()>>11) / (1 << 53)
+}
+
+// Intn returns a pseudo-random number in [0,n), without modulo bias.
+// panic if n <= 0.
+func (r *RNG) Intn(n int) int {
+	if n <= 0 {
+		panic("invalid argument to Intn")
+	}
+	hi, lo := bits.Mul64(r.Uint64(), uint64(n))
+	if lo < uint64(n) {
+		threshold := -uint64(n) % uint64(n)
+		for lo < threshold {
+			hi, lo = bits.Mul64(r.Uint64(), uint64(n))
+		}
+	}
+	return int(hi)
+}
+
+// Shuffle shuffles the given indices in place (Fisher-Yates).
+func (r *RNG) Shuffle(idx []int) {
+	for i := len(idx) - 1; i > 0; i-- {
+		j := r.Intn(i + 1)
+		idx[i], idx[j] = idx[j], idx[i]
+	}
+}
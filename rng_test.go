@@ -0,0 +1,51 @@
+package ml_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/creack/ml"
+)
+
+var _ rand.Source64 = &ml.RNG{}
+
+func TestRNGReproducible(t *testing.T) {
+	r1, r2 := ml.NewRNG(42), ml.NewRNG(42)
+	for i := 0; i < 10; i++ {
+		r1.Uint64()
+	}
+	// Restoring the state replays the sequence.
+	saved := *r1
+	expect := []uint64{r1.Uint64(), r1.Uint64(), r1.Uint64()}
+	r2.State = saved.State
+	for i, elem := range expect {
+		if got := r2.Uint64(); got != elem {
+			t.Fatalf("[%d] Unexpected value after restoring the state: %d != %d", i, got, elem)
+		}
+	}
+	// Known first SplitMix64 output for seed 0.
+	if expect, got := uint64(0xe220a8397b1dcdaf), ml.NewRNG(0).Uint64(); expect != got {
+		t.Fatalf("Unexpected first value.\nExpect:\t%#x\nGot:\t%#x", expect, got)
+	}
+}
+
+func TestRNGShuffle(t *testing.T) {
+	r := ml.NewRNG(1)
+	idx := []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}
+	r.Shuffle(idx)
+	seen := make([]bool, len(idx))
+	for _, elem := range idx {
+		if seen[elem] {
+			t.Fatalf("Shuffle is not a permutation: %v", idx)
+		}
+		seen[elem] = true
+	}
+	for i := 0; i < 1000; i++ {
+		if n := r.Intn(7); n < 0 || n >= 7 {
+			t.Fatalf("Intn out of range: %d", n)
+		}
+		if f := r.Float64(); f < 0 || f >= 1 {
+			t.Fatalf("Float64 out of range: %g", f)
+		}
+	}
+}
@@ -0,0 +1,176 @@
+package ml
+
+import (
+	"context"
+	"fmt"
+	"math"
+)
+
+// Softmax returns the softmax of the given scores: e^z[c] / Sum of e^z.
+// Uses the log-sum-exp trick so large scores do not overflow.
+func Softmax(z []float64) []float64 {
+	lse := logSumExp(z)
+	ret := make([]float64, len(z))
+	for c, elem := range z {
+		ret[c] = math.Exp(elem - lse)
+	}
+	return ret
+}
+
+// logSumExp returns log(Sum of e^z) without overflow.
+func logSumExp(z []float64) float64 {
+	if len(z) == 0 {
+		return math.Inf(-1)
+	}
+	max := z[0]
+	for _, elem := range z[1:] {
+		if elem > max {
+			max = elem
+		}
+	}
+	if math.IsInf(max, 0) {
+		return max
+	}
+	var sum Accumulator
+	for _, elem := range z {
+		sum.Add(math.Exp(elem - max))
+	}
+	return max + math.Log(sum.Sum())
+}
+
+// OneHot returns the dataset targets as a (m,k) one-hot matrix.
+// Either Targets already is the (m,k) one-hot matrix or Y holds integer class labels in [0,k).
+// panic with ErrOutOfBound for invalid labels and ErrBadDim for invalid Y or Targets dimension.
+// NOTE: Does not change the current dataset state.
+func (ds Dataset) OneHot(k int) Matrix {
+	if len(ds.Targets) > 0 {
+		if _, n := ds.Targets.Dim(); n != k {
+			panic(ErrBadDim)
+		}
+		return ds.Targets
+	}
+	m, n := Matrix(ds.Y).Dim()
+	if n != 1 {
+		panic(ErrBadDim)
+	}
+	ret := NewMatrix(m, k)
+	for i, line := range ds.Y {
+		c := int(line[0])
+		if float64(c) != line[0] || c < 0 || c >= k {
+			panic(ErrOutOfBound)
+		}
+		ret[i][c] = 1
+	}
+	return ret
+}
+
+// SoftmaxRegression is the multinomial logistic regression classifier.
+//   - Hypothesis: h(x) = softmax(Θ^T * x), the probability of each class.
+//
+// Θ is a (n+1,k) matrix for n features and k classes.
+// The dataset targets are either Y class labels or Targets one-hot rows, see Dataset.OneHot.
+type SoftmaxRegression struct {
+	Θ Matrix
+}
+
+// Classes returns the number of classes of the model.
+func (b SoftmaxRegression) Classes() int {
+	_, k := b.Θ.Dim()
+	return k
+}
+
+// scores returns Θ^T * x for the given dataset row.
+func (b SoftmaxRegression) scores(row MRow) []float64 {
+	return b.Θ.T().Mul(Matrix{row}.T()).Transpose()[0]
+}
+
+// PredictProba returns the probability of each class for the given features.
+// The x(0) = 1 bias is added if x does not already have it.
+func (b SoftmaxRegression) PredictProba(x Vector) []float64 {
+	row := Matrix(x).Transpose()[0]
+	if len(row) == len(b.Θ)-1 {
+		row = append(MRow{1}, row...)
+	}
+	return Softmax(b.scores(row))
+}
+
+// Predict returns the most probable class for the given features.
+func (b SoftmaxRegression) Predict(x Vector) int {
+	ret := 0
+	proba := b.PredictProba(x)
+	for c, p := range proba {
+		if p > proba[ret] {
+			ret = c
+		}
+	}
+	return ret
+}
+
+// Cost process the categorical cross-entropy cost of the hypothesis on the given dataset.
+// $$-\frac{1}{m}\sum_{i=1}^{m}\sum_{c=1}^{k} y_c^{(i)}\log(h(x^{(i)})_c)$$
+// The log probabilities are computed as z_c - logsumexp(z).
+func (b SoftmaxRegression) Cost(dataset Dataset) float64 {
+	dataset = dataset.withBias(len(b.Θ))
+	y := dataset.OneHot(b.Classes())
+	m, _ := dataset.X.Dim()
+	var sum Accumulator
+	for i := 0; i < m; i++ {
+		z := b.scores(dataset.X[i])
+		lse := logSumExp(z)
+		for c, elem := range z {
+			if y[i][c] != 0 {
+				sum.Add(y[i][c] * (lse - elem))
+			}
+		}
+	}
+	return checkValue("Cost", (1/float64(m))*sum.Sum())
+}
+
+// Parameters implements Model, Θ flattened row by row.
+func (b SoftmaxRegression) Parameters() Vector {
+	return flatten(b.Θ)
+}
+
+// Gradient implements Model, the gradient of the cost flattened row by row.
+func (b SoftmaxRegression) Gradient(dataset Dataset) Vector {
+	return flatten(b.GradientMatrix(dataset))
+}
+
+// GradientMatrix returns the gradient of the cost, a (n+1,k) matrix.
+// $$\frac{1}{m} X^T (h(X) - Y)$$
+func (b SoftmaxRegression) GradientMatrix(dataset Dataset) Matrix {
+	dataset = dataset.withBias(len(b.Θ))
+	y := dataset.OneHot(b.Classes())
+	m, _ := dataset.X.Dim()
+	diff := NewMatrix(m, b.Classes())
+	for i := 0; i < m; i++ {
+		for c, p := range Softmax(b.scores(dataset.X[i])) {
+			diff[i][c] = p - y[i][c]
+		}
+	}
+	return dataset.X.T().Mul(diff.View()).Scale(1 / float64(m)).check("Gradient")
+}
+
+// GradientDescent trains the model on the cross-entropy cost with batch
+// gradient descent until convergence or until the context is done.
+// Same behavior as LinearRegression.GradientDescent.
+func (b *SoftmaxRegression) GradientDescent(ctx context.Context, dataset Dataset, alpha float64, opts TrainOptions) (TrainResult, error) {
+	return Train(ctx, b, dataset.withBias(len(b.Θ)), &SGD{Alpha: alpha}, opts)
+}
+
+// flatten returns the elements of the given matrix as a row-major vector.
+// NOTE: Not a copy, changes to the vector affect the matrix.
+func flatten(ma Matrix) Vector {
+	m, n := ma.shape()
+	ret := make(Vector, 0, m*n)
+	for _, line := range ma {
+		for j := range line {
+			ret = append(ret, line[j:j+1:j+1])
+		}
+	}
+	return ret
+}
+
+func (b SoftmaxRegression) String() string {
+	return fmt.Sprintf("Θ: %v", b.Θ)
+}
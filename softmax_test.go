@@ -0,0 +1,110 @@
+package ml_test
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/creack/ml"
+)
+
+var testSoftmaxDataset = ml.Dataset{
+	X: ml.Matrix{{1}, {2}, {3}, {4}, {5}, {6}, {7}, {8}, {9}},
+	Y: ml.Vector{{0}, {0}, {1}, {0}, {1}, {2}, {1}, {2}, {2}},
+}
+
+func TestSoftmax(t *testing.T) {
+	ret := ml.Softmax([]float64{1, 2, 3})
+	sum := math.Exp(1) + math.Exp(2) + math.Exp(3)
+	for c, expect := range []float64{math.Exp(1) / sum, math.Exp(2) / sum, math.Exp(3) / sum} {
+		if stringify(expect) != stringify(ret[c]) {
+			t.Fatalf("Unexpected softmax[%d].\nExpect:\t%g\nGot:\t%g", c, expect, ret[c])
+		}
+	}
+	// Large scores must not overflow.
+	ret = ml.Softmax([]float64{1000, 1000})
+	if stringify(ret[0]) != stringify(0.5) || stringify(ret[1]) != stringify(0.5) {
+		t.Fatalf("Unexpected softmax for large scores: %v", ret)
+	}
+}
+
+func TestOneHot(t *testing.T) {
+	expect := ml.Matrix{{1, 0, 0}, {0, 0, 1}, {0, 1, 0}}
+	labels := ml.Dataset{Y: ml.Vector{{0}, {2}, {1}}}
+	if ret := labels.OneHot(3); !ret.Equal(expect) {
+		t.Fatalf("Unexpected one-hot matrix\nGot:\n%s\nExpect:\n%s\n", ret, expect)
+	}
+	oneHot := ml.Dataset{Targets: expect}
+	if ret := oneHot.OneHot(3); !ret.Equal(expect) {
+		t.Fatalf("Unexpected one-hot passthrough\nGot:\n%s\nExpect:\n%s\n", ret, expect)
+	}
+	for i, ds := range []ml.Dataset{
+		{Y: ml.Vector{{0}, {3}}},
+		{Y: ml.Vector{{0}, {0.5}}},
+		{Targets: ml.Matrix{{0, 1}, {1, 0}}},
+	} {
+		func() {
+			defer func() {
+				if err := recover(); err == nil {
+					t.Fatalf("[%d] no panic received for invalid targets", i)
+				}
+			}()
+			ds.OneHot(3)
+		}()
+	}
+}
+
+func TestSoftmaxRegression(t *testing.T) {
+	sr := &ml.SoftmaxRegression{Θ: ml.NewMatrix(2, 3)}
+	if expect, got := stringify(math.Log(3)), stringify(sr.Cost(testSoftmaxDataset)); expect != got {
+		t.Fatalf("Unexpected cost for Θ = 0.\nExpect:\t%s\nGot:\t%s", expect, got)
+	}
+	if _, err := sr.GradientDescent(context.Background(), testSoftmaxDataset, 0.2, ml.GradientDescentOptions()); err != nil {
+		t.Fatalf("Error training the model: %s", err)
+	}
+
+	// At the optimum, the gradient is 0.
+	if grad := sr.GradientMatrix(testSoftmaxDataset); !approxEqual(grad, ml.NewMatrix(2, 3), 1e-6) {
+		t.Fatalf("Non zero gradient after training\n%s\n", grad)
+	}
+	for i, elem := range []struct {
+		x      float64
+		expect int
+	}{
+		{0, 0},
+		{1.5, 0},
+		{5, 1},
+		{9, 2},
+	} {
+		if got := sr.Predict(ml.Vector{{elem.x}}); got != elem.expect {
+			t.Errorf("[%d] Unexpected class for %g: %d (p=%v)", i, elem.x, got, sr.PredictProba(ml.Vector{{elem.x}}))
+		}
+	}
+
+	// One-hot targets give the same cost as labels.
+	oneHot := ml.Dataset{X: testSoftmaxDataset.X, Targets: testSoftmaxDataset.OneHot(3)}
+	if expect, got := stringify(sr.Cost(testSoftmaxDataset)), stringify(sr.Cost(oneHot)); expect != got {
+		t.Fatalf("Unexpected one-hot cost.\nExpect:\t%s\nGot:\t%s", expect, got)
+	}
+}
+
+func TestOneHotDatasetJSON(t *testing.T) {
+	ds := ml.Dataset{X: ml.Matrix{{1}, {2}}, Targets: ml.Matrix{{1, 0}, {0, 1}}}
+	buf, err := json.Marshal(ds)
+	if err != nil {
+		t.Fatalf("Error encoding one-hot dataset: %s", err)
+	}
+	var ds2 ml.Dataset
+	if err := json.Unmarshal(buf, &ds2); err != nil {
+		t.Fatalf("Error decoding one-hot dataset: %s", err)
+	}
+	if !ds2.Targets.Equal(ds.Targets) || ds2.Y != nil {
+		t.Fatalf("Unexpected decoded one-hot targets\n%s\n", ds2.Targets)
+	}
+
+	// Y is a vector, one-hot rows go to Targets.
+	if err := json.Unmarshal([]byte(`{"y":[[1,0],[0,1]]}`), &ds2); err != ml.ErrNotAVector {
+		t.Fatalf("Unexpected error decoding one-hot Y: %v", err)
+	}
+}
@@ -0,0 +1,61 @@
+package ml
+
+import (
+	"math"
+)
+
+// Accumulator is a compensated summation accumulator.
+// It uses the Neumaier variant of the Kahan summation: the low order bits lost
+// at each addition are kept in a separate compensation term, so the error of the
+// sum does not grow with the number of elements.
+// The zero value is an empty sum ready to use.
+type Accumulator struct {
+	sum, c float64
+}
+
+// Add adds the given value to the sum.
+func (a *Accumulator) Add(x float64) {
+	t := a.sum + x
+	if math.Abs(a.sum) >= math.Abs(x) {
+		a.c += (a.sum - t) + x
+	} else {
+		a.c += (x - t) + a.sum
+	}
+	a.sum = t
+}
+
+// Sum returns the compensated sum.
+func (a Accumulator) Sum() float64 {
+	// On overflow the compensation term is NaN (Inf-Inf), report the overflow instead.
+	if math.IsInf(a.sum, 0) {
+		return a.sum
+	}
+	return a.sum + a.c
+}
+
+// Sum returns the compensated (Neumaier) sum of the given values.
+func Sum(xs []float64) float64 {
+	var acc Accumulator
+	for _, x := range xs {
+		acc.Add(x)
+	}
+	return acc.Sum()
+}
+
+// pairwiseBlock is the size under which PairwiseSum falls back to a naive loop.
+const pairwiseBlock = 128
+
+// PairwiseSum returns the sum of the given values using pairwise (cascade) summation.
+// The error grows in O(log n) instead of O(n) for the naive loop, it is
+// less accurate than Sum but about as fast as the naive loop.
+func PairwiseSum(xs []float64) float64 {
+	if len(xs) <= pairwiseBlock {
+		sum := 0.
+		for _, x := range xs {
+			sum += x
+		}
+		return sum
+	}
+	mid := len(xs) / 2
+	return PairwiseSum(xs[:mid]) + PairwiseSum(xs[mid:])
+}
@@ -0,0 +1,84 @@
+package ml_test
+
+import (
+	"math"
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/creack/ml"
+)
+
+// naiveSum is the plain accumulation loop, used as reference.
+func naiveSum(xs []float64) float64 {
+	sum := 0.
+	for _, x := range xs {
+		sum += x
+	}
+	return sum
+}
+
+// exactSum computes the correctly rounded sum of the given values.
+func exactSum(xs []float64) float64 {
+	sum := new(big.Float).SetPrec(2048)
+	for _, x := range xs {
+		sum.Add(sum, big.NewFloat(x))
+	}
+	ret, _ := sum.Float64()
+	return ret
+}
+
+// sumDataset returns n values with a wide dynamic range and mixed signs.
+func sumDataset(n int) []float64 {
+	rnd := rand.New(rand.NewSource(42))
+	xs := make([]float64, n)
+	for i := range xs {
+		xs[i] = (rnd.Float64() - 0.3) * math.Pow(10, float64(rnd.Intn(8)))
+	}
+	return xs
+}
+
+func relErr(got, expect float64) float64 {
+	return math.Abs(got-expect) / math.Abs(expect)
+}
+
+func TestSumAccuracy(t *testing.T) {
+	xs := sumDataset(1e6)
+	expect := exactSum(xs)
+
+	naive, pairwise, compensated := relErr(naiveSum(xs), expect), relErr(ml.PairwiseSum(xs), expect), relErr(ml.Sum(xs), expect)
+	t.Logf("relative error: naive %g, pairwise %g, compensated %g", naive, pairwise, compensated)
+	if compensated > 1e-16 {
+		t.Fatalf("Compensated sum is not accurate: relative error %g", compensated)
+	}
+	if pairwise > naive {
+		t.Fatalf("Pairwise sum less accurate than naive sum: %g > %g", pairwise, naive)
+	}
+}
+
+func TestVectorSum(t *testing.T) {
+	v := ml.NewVector(1e6)
+	xs := make([]float64, len(v))
+	for i := range v {
+		v[i][0] = 0.1
+		xs[i] = 0.1
+	}
+	if expect, got := exactSum(xs), v.Sum(); expect != got {
+		t.Fatalf("Unexpected vector sum.\nExpect:\t%.17g\nGot:\t%.17g", expect, got)
+	}
+}
+
+func benchmarkSum(b *testing.B, fct func([]float64) float64) {
+	xs := sumDataset(1e6)
+	expect := exactSum(xs)
+	b.ResetTimer()
+	var sum float64
+	for i := 0; i < b.N; i++ {
+		sum = fct(xs)
+	}
+	b.ReportMetric(relErr(sum, expect), "relerr")
+}
+
+func BenchmarkSumNaive(b *testing.B)       { benchmarkSum(b, naiveSum) }
+func BenchmarkSumPairwise(b *testing.B)    { benchmarkSum(b, ml.PairwiseSum) }
+func BenchmarkSumCompensated(b *testing.B) { benchmarkSum(b, ml.Sum) }
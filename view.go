@@ -0,0 +1,143 @@
+package ml
+
+// View is a lazy strided window over a parent matrix.
+// Reads and writes go through to the parent, nothing is copied
+// until Materialize is called.
+// A View can be transposed, restricted to a row/col range or
+// step-sliced, and those operations compose.
+type View struct {
+	parent     Matrix
+	i0, j0     int  // Parent index of the view (0,0) element.
+	di, dj     int  // Parent step between two view rows/cols.
+	m, n       int  // Dimension of the view in parent orientation.
+	transposed bool // When set, view (i,j) maps to parent orientation (j,i).
+}
+
+// View returns a view of the whole current matrix.
+func (ma Matrix) View() View {
+	m, n := ma.Dim()
+	if len(ma) > 0 && len(ma[0]) == 0 {
+		n = 0
+	}
+	return View{parent: ma, di: 1, dj: 1, m: m, n: n}
+}
+
+// T returns a transposed view of the current matrix.
+// NOTE: Not a copy, changes to the view affect the matrix.
+func (ma Matrix) T() View {
+	return ma.View().T()
+}
+
+// Slice returns a view of the rows [r0:r1] by step rs and the cols [c0:c1] by step cs.
+// NOTE: Not a copy, changes to the view affect the matrix.
+func (ma Matrix) Slice(r0, r1, rs, c0, c1, cs int) View {
+	return ma.View().Slice(r0, r1, rs, c0, c1, cs)
+}
+
+// T returns a transposed view of the current vector.
+// NOTE: Not a copy, changes to the view affect the vector.
+func (v Vector) T() View {
+	return Matrix(v).T()
+}
+
+// Dim returns the dimension of the view.
+func (v View) Dim() (int, int) {
+	if v.transposed {
+		return v.n, v.m
+	}
+	return v.m, v.n
+}
+
+// At returns the (i,j) element of the view.
+func (v View) At(i, j int) float64 {
+	i, j = v.index(i, j)
+	return v.parent[i][j]
+}
+
+// Set sets the (i,j) element of the view.
+// NOTE: Changes the state of the parent matrix.
+func (v View) Set(i, j int, elem float64) {
+	i, j = v.index(i, j)
+	v.parent[i][j] = elem
+}
+
+// index converts view (i,j) indices to parent ones.
+func (v View) index(i, j int) (int, int) {
+	if v.transposed {
+		i, j = j, i
+	}
+	if i < 0 || j < 0 || i >= v.m || j >= v.n {
+		panic(ErrOutOfBound)
+	}
+	return v.i0 + i*v.di, v.j0 + j*v.dj
+}
+
+// T returns the transposed view of the current view.
+func (v View) T() View {
+	v.transposed = !v.transposed
+	return v
+}
+
+// Rows returns a view of the rows [from:to] of the current view.
+func (v View) Rows(from, to int) View {
+	_, n := v.Dim()
+	return v.Slice(from, to, 1, 0, n, 1)
+}
+
+// Cols returns a view of the cols [from:to] of the current view.
+func (v View) Cols(from, to int) View {
+	m, _ := v.Dim()
+	return v.Slice(0, m, 1, from, to, 1)
+}
+
+// Slice returns a view of the rows [r0:r1] by step rs and the cols [c0:c1] by step cs
+// of the current view. Indices are expressed in the current view orientation.
+func (v View) Slice(r0, r1, rs, c0, c1, cs int) View {
+	if r0 < 0 || r1 < 0 || c0 < 0 || c1 < 0 || rs <= 0 || cs <= 0 {
+		panic(ErrNegativeIndex)
+	}
+	m, n := v.Dim()
+	if r0 > r1 || c0 > c1 || r1 > m || c1 > n {
+		panic(ErrOutOfBound)
+	}
+	if v.transposed {
+		// View rows are parent cols.
+		r0, r1, rs, c0, c1, cs = c0, c1, cs, r0, r1, rs
+	}
+	v.i0, v.di, v.m = v.i0+r0*v.di, v.di*rs, (r1-r0+rs-1)/rs
+	v.j0, v.dj, v.n = v.j0+c0*v.dj, v.dj*cs, (c1-c0+cs-1)/cs
+	return v
+}
+
+// Materialize returns a copy of the view as a new matrix.
+func (v View) Materialize() Matrix {
+	m, n := v.Dim()
+	ret := NewMatrix(m, n)
+	for i := 0; i < m; i++ {
+		for j := 0; j < n; j++ {
+			ret[i][j] = v.At(i, j)
+		}
+	}
+	return ret
+}
+
+// Mul returns the result of the current view multiplied by the given one.
+// NOTE: Does not change the parent matrices state.
+func (v View) Mul(v2 View) Matrix {
+	m1, n1 := v.Dim()
+	m2, n2 := v2.Dim()
+	if n1 != m2 {
+		panic(ErrBadDim)
+	}
+	ret := NewMatrix(m1, n2)
+	for i := 0; i < m1; i++ {
+		for j := 0; j < n2; j++ {
+			var sum Accumulator
+			for k := 0; k < n1; k++ {
+				sum.Add(v.At(i, k) * v2.At(k, j))
+			}
+			ret[i][j] = sum.Sum()
+		}
+	}
+	return ret.check("Mul")
+}
@@ -0,0 +1,83 @@
+package ml_test
+
+import (
+	"testing"
+
+	"github.com/creack/ml"
+)
+
+func TestViewTranspose(t *testing.T) {
+	m1 := ml.Matrix{
+		{1, 2, 3},
+		{4, 5, 6},
+	}
+	v := m1.T()
+	if m, n := v.Dim(); m != 3 || n != 2 {
+		t.Fatalf("Transposed view should have dimension (3,2). Got: (%d,%d)", m, n)
+	}
+	if ret := v.Materialize(); !ret.Equal(m1.Transpose()) {
+		t.Fatalf("Unexpected transposed view\nGot:\n%s\nExpect:\n%s\n", ret, m1.Transpose())
+	}
+	v.Set(2, 0, 42)
+	if m1[0][2] != 42 {
+		t.Fatalf("Change to the view did not reach the parent matrix\n%s\n", m1)
+	}
+	if ret := v.T().Materialize(); !ret.Equal(m1) {
+		t.Fatalf("Double transposed view differs from parent\nGot:\n%s\nExpect:\n%s\n", ret, m1)
+	}
+}
+
+func TestViewSlice(t *testing.T) {
+	m1 := ml.Matrix{
+		{1, 2, 3, 4},
+		{5, 6, 7, 8},
+		{9, 10, 11, 12},
+		{13, 14, 15, 16},
+		{17, 18, 19, 20},
+	}
+	for i, elem := range []struct {
+		view   ml.View
+		expect ml.Matrix
+	}{
+		{m1.Slice(0, 5, 2, 0, 4, 3), ml.Matrix{{1, 4}, {9, 12}, {17, 20}}},
+		{m1.View().Rows(1, 3), ml.Matrix{{5, 6, 7, 8}, {9, 10, 11, 12}}},
+		{m1.View().Cols(2, 3), ml.Matrix{{3}, {7}, {11}, {15}, {19}}},
+		{m1.T().Rows(1, 2), ml.Matrix{{2, 6, 10, 14, 18}}},
+		{m1.T().Slice(0, 4, 3, 1, 5, 2), ml.Matrix{{5, 13}, {8, 16}}},
+		{m1.Slice(1, 5, 2, 1, 4, 1).T().Cols(1, 2), ml.Matrix{{14}, {15}, {16}}},
+		{m1.Slice(2, 2, 1, 0, 4, 1), ml.Matrix{}},
+	} {
+		if ret := elem.view.Materialize(); !ret.Equal(elem.expect) {
+			t.Fatalf("[%d] Unexpected view\nGot:\n%s\nExpect:\n%s\n", i, ret, elem.expect)
+		}
+	}
+}
+
+func TestViewOutOfBound(t *testing.T) {
+	m1 := ml.NewMatrix(2, 3)
+	for i, fct := range []func(){
+		func() { m1.Slice(0, 3, 1, 0, 3, 1) },
+		func() { m1.Slice(0, 2, 0, 0, 3, 1) },
+		func() { m1.T().At(0, 2) },
+		func() { m1.T().Rows(1, 4) },
+	} {
+		func() {
+			defer func() {
+				if err := recover(); err == nil {
+					t.Fatalf("[%d] no panic received for out of bound view", i)
+				}
+			}()
+			fct()
+		}()
+	}
+}
+
+func TestViewMul(t *testing.T) {
+	m1 := ml.Matrix{
+		{1, 2, 3},
+		{4, 5, 6},
+	}
+	if ret, expect := m1.T().Mul(m1.View()), m1.Transpose().Mul(m1); !ret.Equal(expect) {
+		t.Fatalf("Unexpected view product\nGot:\n%s\nExpect:\n%s\n", ret, expect)
+	}
+}